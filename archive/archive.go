@@ -0,0 +1,160 @@
+package archive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/message/database/bolt"
+	"github.com/oklog/ulid"
+)
+
+// Formats ...
+const (
+	// NDJSON writes gzip compressed newline delimited JSON files.
+	NDJSON = "ndjson"
+	// Parquet writes snappy compressed parquet files.
+	Parquet = "parquet"
+)
+
+// Record is an exported message with its status history.
+type Record struct {
+	ID              string                `json:"id"`
+	Channel         string                `json:"channel"`
+	Provider        string                `json:"provider"`
+	Content         string                `json:"content"`
+	ContentType     string                `json:"content_type,omitempty"`
+	Status          string                `json:"status"`
+	Client          string                `json:"client,omitempty"`
+	Tenant          string                `json:"tenant,omitempty"`
+	Revision        int32                 `json:"revision,omitempty"`
+	Template        string                `json:"template,omitempty"`
+	TemplateVersion int32                 `json:"template_version,omitempty"`
+	Locale          string                `json:"locale,omitempty"`
+	Render          bool                  `json:"render,omitempty"`
+	History         []*message.Transition `json:"history"`
+
+	// EncryptedContent replaces Content when the encryption at rest is
+	// enabled, so the archives keep it sealed.
+	EncryptedContent *encryption.Envelope `json:"encrypted_content,omitempty"`
+}
+
+// NewRecord ...
+func NewRecord(r *bolt.Record) *Record {
+	m := r.Message
+	return &Record{
+		ID:          m.ID.String(),
+		Channel:     m.Channel,
+		Provider:    m.Provider,
+		Content:     m.Content,
+		ContentType: m.ContentType,
+		Status:      m.Status,
+		Client:      m.Client,
+		Tenant:      m.Tenant,
+		Revision:    m.Revision,
+		History:     r.History,
+
+		Template:        m.Template,
+		TemplateVersion: m.TemplateVersion,
+		Locale:          m.Locale,
+		Render:          m.Render,
+
+		EncryptedContent: r.Sealed,
+	}
+}
+
+// Stored returns the message, the history and the sealed content stored on
+// the record.
+func (r *Record) Stored() (*bolt.Record, error) {
+	id, err := ulid.Parse(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &message.Message{
+		ID:          id,
+		Channel:     r.Channel,
+		Provider:    r.Provider,
+		Content:     r.Content,
+		ContentType: r.ContentType,
+		Status:      r.Status,
+		Client:      r.Client,
+		Tenant:      r.Tenant,
+		Revision:    r.Revision,
+
+		Template:        r.Template,
+		TemplateVersion: r.TemplateVersion,
+		Locale:          r.Locale,
+		Render:          r.Render,
+	}
+
+	return &bolt.Record{
+		Message: m,
+		History: r.History,
+		Sealed:  r.EncryptedContent,
+	}, nil
+}
+
+// Filter selects the messages to export.
+type Filter struct {
+	// Channel matches the channel of the message, empty matches any.
+	Channel string
+
+	// Status matches the status of the message, empty matches any.
+	Status string
+
+	// Since and Until limit the time encoded in the message ULID, zero
+	// values are not checked.
+	Since time.Time
+	Until time.Time
+
+	// Archived includes the messages moved to the archive by the
+	// retention policy.
+	Archived bool
+}
+
+// Matches reports whether m is selected by the filter.
+func (f *Filter) Matches(m *message.Message) bool {
+	if f.Channel != "" && f.Channel != m.Channel {
+		return false
+	}
+	if f.Status != "" && f.Status != m.Status {
+		return false
+	}
+
+	t := ulid.Time(m.ID.Time())
+	if !f.Since.IsZero() && t.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !t.Before(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Writer writes records to a single file.
+type Writer interface {
+	Write(r *Record) error
+	Close() error
+}
+
+// Reader reads records from a single file.
+type Reader interface {
+	// Read returns the next record, or io.EOF at the end of the file.
+	Read() (*Record, error)
+	Close() error
+}
+
+// Ext returns the file extension used by format.
+func Ext(format string) (string, error) {
+	switch format {
+	case NDJSON:
+		return ".ndjson.gz", nil
+	case Parquet:
+		return ".parquet", nil
+	}
+
+	return "", fmt.Errorf("unknown archive format %q", format)
+}
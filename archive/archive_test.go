@@ -0,0 +1,265 @@
+package archive
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/message/database/bolt"
+	"github.com/oklog/ulid"
+
+	db "github.com/microapis/messages-core/message/database"
+)
+
+func newStore(t *testing.T) *bolt.MessageStore {
+	t.Helper()
+	dst, err := db.NewBoltDatastore(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dst.DB.Close() })
+
+	ss, err := bolt.NewMessageStore(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+func newSink(t *testing.T) *DirSink {
+	t.Helper()
+	s, err := NewDirSink(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFormats(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []*Record{
+		{
+			ID:      ulid.MustNew(ulid.Timestamp(at), rand.Reader).String(),
+			Channel: "email",
+			Content: "Hi",
+			Status:  message.Received,
+			History: []*message.Transition{{To: message.Received, Time: at, Source: message.SourceAPI, Client: "a"}},
+		},
+		{
+			ID:              ulid.MustNew(ulid.Timestamp(at.Add(time.Second)), rand.Reader).String(),
+			Channel:         "email",
+			Provider:        "smtp",
+			ContentType:     "application/json",
+			Status:          message.Sent,
+			Client:          "a",
+			Tenant:          "billing",
+			Revision:        2,
+			Template:        "welcome",
+			TemplateVersion: 3,
+			Locale:          "es",
+			History: []*message.Transition{
+				{To: message.Received, Time: at, Source: message.SourceAPI},
+				{From: message.Received, To: message.Approved, Time: at, Source: message.SourceAPI},
+			},
+			EncryptedContent: &encryption.Envelope{KeyID: "k1", Key: []byte("key"), Nonce: []byte("nonce"), Data: []byte("data")},
+		},
+		{
+			ID:       ulid.MustNew(ulid.Timestamp(at.Add(2*time.Second)), rand.Reader).String(),
+			Channel:  "sms",
+			Content:  `{"name":"Ana"}`,
+			Status:   message.Received,
+			Template: "welcome",
+			Render:   true,
+			History:  []*message.Transition{},
+		},
+	}
+
+	for _, format := range []string{NDJSON, Parquet} {
+		t.Run(format, func(t *testing.T) {
+			s := newSink(t)
+			f, err := s.Create("records")
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, err := NewWriter(format, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range records {
+				if err := w.Write(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			rf, err := s.Open("records")
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := NewReader(format, rf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			for _, want := range records {
+				got, err := r.Read()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Read() = %+v, want %+v", got, want)
+				}
+			}
+			if _, err := r.Read(); err != io.EOF {
+				t.Errorf("Read() at the end = %v, want %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := newStore(t)
+	for i, channel := range []string{"email", "sms", "email"} {
+		m := message.Message{
+			ID:      ulid.MustNew(ulid.Now(), rand.Reader),
+			Channel: channel,
+			Content: "content",
+		}
+		if i == 2 {
+			m.Template, m.Render = "welcome", true
+		}
+		if err := src.AddMessage(m, message.SourceAPI); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored, err := src.Page(false, ulid.ULID{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var emails []*bolt.Record
+	for _, r := range stored {
+		if r.Message.Channel == "email" {
+			emails = append(emails, r)
+		}
+	}
+
+	tests := []struct {
+		format     string
+		maxRecords int
+		filter     Filter
+		files      int
+		want       []*bolt.Record
+	}{
+		{NDJSON, 0, Filter{}, 1, stored},
+		{Parquet, 0, Filter{}, 1, stored},
+		{NDJSON, 2, Filter{}, 2, stored},
+		{Parquet, 1, Filter{Channel: "email"}, 2, emails},
+	}
+
+	for _, tt := range tests {
+		s := newSink(t)
+		e := &Exporter{Store: src, Sink: s, Format: tt.format, MaxRecords: tt.maxRecords}
+		files, n, err := e.Export(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Export() = %v", tt.format, err)
+		}
+		if len(files) != tt.files || n != len(tt.want) {
+			t.Errorf("%s: Export() = %d files, %d messages, want %d, %d", tt.format, len(files), n, tt.files, len(tt.want))
+		}
+
+		dst := newStore(t)
+		if n, err := Import(s, dst); err != nil || n != len(tt.want) {
+			t.Errorf("%s: Import() = %d, %v, want %d", tt.format, n, err, len(tt.want))
+		}
+		got, err := dst.Page(true, ulid.ULID{}, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: imported %+v, want %+v", tt.format, got, tt.want)
+		}
+
+		// the messages still stored are skipped.
+		if n, err := Import(s, src); err != nil || n != 0 {
+			t.Errorf("%s: Import() of stored messages = %d, %v, want 0", tt.format, n, err)
+		}
+	}
+}
+
+func TestImportTenants(t *testing.T) {
+	src := newStore(t)
+	billing, err := src.For("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ss := range []*bolt.MessageStore{src, billing} {
+		m := message.Message{
+			ID:      ulid.MustNew(ulid.Now(), rand.Reader),
+			Channel: "email",
+			Content: "content",
+			Tenant:  ss.Tenant,
+		}
+		if err := ss.AddMessage(m, message.SourceAPI); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := newSink(t)
+	for _, ss := range []*bolt.MessageStore{src, billing} {
+		ts, err := s.For(ss.Tenant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := &Exporter{Store: ss, Sink: ts, Format: NDJSON}
+		if _, _, err := e.Export(Filter{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// every tenant only imports its files.
+	dst := newStore(t)
+	for _, tenantID := range []string{"", "billing", "support"} {
+		ss, err := dst.For(tenantID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 1
+		if tenantID == "support" {
+			want = 0
+		}
+		if n, err := Import(s, ss); err != nil || n != want {
+			t.Errorf("Import() on tenant %q = %d, %v, want %d", tenantID, n, err, want)
+		}
+	}
+
+	// the files of a tenant holding messages of another are rejected.
+	bs, err := s.For("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := bs.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := newStore(t).For("support")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := os.Rename(filepath.Join(bs.(*DirSink).Dir, name), filepath.Join(s.Dir, tenantDir, "support", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := Import(s, ss); err == nil || n != 0 {
+		t.Errorf("Import() of the messages of another tenant = %d, %v, want an error", n, err)
+	}
+}
@@ -0,0 +1,221 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/microapis/messages-core/message/database/bolt"
+	"github.com/oklog/ulid"
+
+	mstore "github.com/microapis/messages-core/store"
+)
+
+// Exporter writes the messages of a store to rotating archive files.
+type Exporter struct {
+	Store *bolt.MessageStore
+	Sink  Sink
+
+	// Format is NDJSON or Parquet.
+	Format string
+
+	// MaxRecords is the number of records written on each file before
+	// rotating to a new one, 0 writes a single file.
+	MaxRecords int
+
+	// Prefix is prepended to the names of the files.
+	Prefix string
+}
+
+// pageSize is the number of messages read from the store at once, so its
+// transaction is not held while writing to a slow sink.
+const pageSize = 500
+
+// Export writes the messages selected by f and returns the names of the
+// written files and the number of exported messages.
+func (e *Exporter) Export(f Filter) ([]string, int, error) {
+	ext, err := Ext(e.Format)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	prefix := e.Prefix
+	if prefix == "" {
+		prefix = "messages"
+	}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	var (
+		w     Writer
+		files []string
+		n     int
+	)
+
+	write := func(r *bolt.Record) error {
+		if !f.Matches(r.Message) {
+			return nil
+		}
+
+		if w != nil && e.MaxRecords > 0 && n%e.MaxRecords == 0 {
+			if err := w.Close(); err != nil {
+				return err
+			}
+			w = nil
+		}
+
+		if w == nil {
+			name := fmt.Sprintf("%s-%s-%04d%s", prefix, stamp, len(files), ext)
+			file, err := e.Sink.Create(name)
+			if err != nil {
+				return err
+			}
+			if w, err = NewWriter(e.Format, file); err != nil {
+				file.Close()
+				return err
+			}
+			files = append(files, name)
+		}
+
+		if err := w.Write(NewRecord(r)); err != nil {
+			return err
+		}
+		n++
+
+		return nil
+	}
+
+	buckets := []bool{false}
+	if f.Archived {
+		buckets = append(buckets, true)
+	}
+	for _, archived := range buckets {
+		var after ulid.ULID
+		for err == nil {
+			var records []*bolt.Record
+			if records, err = e.Store.Page(archived, after, pageSize); err != nil || len(records) == 0 {
+				break
+			}
+			for _, r := range records {
+				if err = write(r); err != nil {
+					break
+				}
+			}
+			after = records[len(records)-1].Message.ID
+		}
+	}
+	if w != nil {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return files, n, err
+	}
+
+	return files, n, nil
+}
+
+// Import restores the messages of every archive file of the tenant of
+// store on the sink as archived messages and returns the number of
+// restored messages. The messages still stored are skipped, and it fails
+// on the messages of another tenant.
+func Import(s Sink, store *bolt.MessageStore) (int, error) {
+	s, err := s.For(store.Tenant)
+	if err != nil {
+		return 0, err
+	}
+
+	names, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, name := range names {
+		format := formatOf(name)
+		if format == "" {
+			continue
+		}
+
+		f, err := s.Open(name)
+		if err != nil {
+			return n, err
+		}
+		r, err := NewReader(format, f)
+		if err != nil {
+			return n, fmt.Errorf("%s: %v", name, err)
+		}
+
+		for {
+			rec, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				r.Close()
+				return n, fmt.Errorf("%s: %v", name, err)
+			}
+
+			if rec.Tenant != store.Tenant {
+				r.Close()
+				return n, fmt.Errorf("%s: message %s of tenant %q imported on tenant %q", name, rec.ID, rec.Tenant, store.Tenant)
+			}
+
+			stored, err := rec.Stored()
+			if err != nil {
+				r.Close()
+				return n, fmt.Errorf("%s: %v", name, err)
+			}
+			if err := store.Restore(stored); err != nil {
+				if errors.Is(err, mstore.ErrConflict) {
+					continue
+				}
+				r.Close()
+				return n, err
+			}
+			n++
+		}
+
+		r.Close()
+	}
+
+	return n, nil
+}
+
+// NewWriter returns the Writer of format over w.
+func NewWriter(format string, w io.WriteCloser) (Writer, error) {
+	switch format {
+	case NDJSON:
+		return NewNDJSONWriter(w), nil
+	case Parquet:
+		return NewParquetWriter(w)
+	}
+
+	return nil, fmt.Errorf("unknown archive format %q", format)
+}
+
+// NewReader returns the Reader of format over r.
+func NewReader(format string, r io.ReadCloser) (Reader, error) {
+	switch format {
+	case NDJSON:
+		return NewNDJSONReader(r)
+	case Parquet:
+		return NewParquetReader(r)
+	}
+
+	return nil, fmt.Errorf("unknown archive format %q", format)
+}
+
+// formatOf returns the format of the file name, or empty if it is not an
+// archive file.
+func formatOf(name string) string {
+	for _, format := range []string{NDJSON, Parquet} {
+		if ext, _ := Ext(format); strings.HasSuffix(name, ext) {
+			return format
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,73 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+type ndjsonWriter struct {
+	w   io.WriteCloser
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter returns a Writer of gzip compressed newline delimited
+// JSON. Closing it closes w.
+func NewNDJSONWriter(w io.WriteCloser) Writer {
+	gz := gzip.NewWriter(w)
+
+	return &ndjsonWriter{
+		w:   w,
+		gz:  gz,
+		enc: json.NewEncoder(gz),
+	}
+}
+
+func (nw *ndjsonWriter) Write(r *Record) error {
+	return nw.enc.Encode(r)
+}
+
+func (nw *ndjsonWriter) Close() error {
+	if err := nw.gz.Close(); err != nil {
+		nw.w.Close()
+		return err
+	}
+
+	return nw.w.Close()
+}
+
+type ndjsonReader struct {
+	r   io.ReadCloser
+	gz  *gzip.Reader
+	dec *json.Decoder
+}
+
+// NewNDJSONReader returns a Reader of gzip compressed newline delimited
+// JSON. Closing it closes r.
+func NewNDJSONReader(r io.ReadCloser) (Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ndjsonReader{
+		r:   r,
+		gz:  gz,
+		dec: json.NewDecoder(gz),
+	}, nil
+}
+
+func (nr *ndjsonReader) Read() (*Record, error) {
+	var r Record
+	if err := nr.dec.Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (nr *ndjsonReader) Close() error {
+	nr.gz.Close()
+	return nr.r.Close()
+}
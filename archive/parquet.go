@@ -0,0 +1,211 @@
+package archive
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRecord is the parquet schema of a Record, the history is kept as
+// a JSON encoded column. The columns added after the first files are
+// optional, so those files can still be read.
+type parquetRecord struct {
+	ID       string `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Channel  string `parquet:"name=channel, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Provider string `parquet:"name=provider, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Content  string `parquet:"name=content, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Status   string `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	History  string `parquet:"name=history, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Client   string `parquet:"name=client, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Tenant   string `parquet:"name=tenant, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	Revision        *int32  `parquet:"name=revision, type=INT32, repetitiontype=OPTIONAL"`
+	ContentType     *string `parquet:"name=content_type, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Template        *string `parquet:"name=template, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	TemplateVersion *int32  `parquet:"name=template_version, type=INT32, repetitiontype=OPTIONAL"`
+	Locale          *string `parquet:"name=locale, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Render          *bool   `parquet:"name=render, type=BOOLEAN, repetitiontype=OPTIONAL"`
+
+	// EncryptedContent is the JSON encoded envelope of the sealed content.
+	EncryptedContent *string `parquet:"name=encrypted_content, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+type parquetWriter struct {
+	w  io.WriteCloser
+	pw *writer.ParquetWriter
+}
+
+// NewParquetWriter returns a Writer of snappy compressed parquet. Closing
+// it closes w.
+func NewParquetWriter(w io.WriteCloser) (Writer, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRecord), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetWriter{
+		w:  w,
+		pw: pw,
+	}, nil
+}
+
+func (pw *parquetWriter) Write(r *Record) error {
+	history, err := json.Marshal(r.History)
+	if err != nil {
+		return err
+	}
+
+	var sealed *string
+	if r.EncryptedContent != nil {
+		b, err := json.Marshal(r.EncryptedContent)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		sealed = &s
+	}
+
+	return pw.pw.Write(parquetRecord{
+		ID:       r.ID,
+		Channel:  r.Channel,
+		Provider: r.Provider,
+		Content:  r.Content,
+		Status:   r.Status,
+		History:  string(history),
+		Client:   r.Client,
+		Tenant:   r.Tenant,
+
+		Revision:        &r.Revision,
+		ContentType:     &r.ContentType,
+		Template:        &r.Template,
+		TemplateVersion: &r.TemplateVersion,
+		Locale:          &r.Locale,
+		Render:          &r.Render,
+
+		EncryptedContent: sealed,
+	})
+}
+
+func (pw *parquetWriter) Close() error {
+	if err := pw.pw.WriteStop(); err != nil {
+		pw.w.Close()
+		return err
+	}
+
+	return pw.w.Close()
+}
+
+type parquetReader struct {
+	columns map[string][]interface{}
+	rows    int
+	next    int
+}
+
+// NewParquetReader returns a Reader of parquet files.
+//
+// Parquet needs random access to the file, so r is read in memory and
+// closed before returning. The file is read by column, so the files
+// without the columns added later are read with their zero values.
+func NewParquetReader(r io.ReadCloser) (Reader, error) {
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := buffer.NewBufferFile(b)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.ReadStop()
+
+	rows := pr.GetNumRows()
+	columns := make(map[string][]interface{})
+	root := pr.SchemaHandler.GetRootExName()
+	for _, name := range pr.SchemaHandler.ValueColumns {
+		values, _, _, err := pr.ReadColumnByPath(name, rows)
+		if err != nil {
+			return nil, err
+		}
+		columns[strings.TrimPrefix(pr.SchemaHandler.InPathToExPath[name], root+common.PAR_GO_PATH_DELIMITER)] = values
+	}
+
+	return &parquetReader{
+		columns: columns,
+		rows:    int(rows),
+	}, nil
+}
+
+func (pr *parquetReader) Read() (*Record, error) {
+	if pr.next == pr.rows {
+		return nil, io.EOF
+	}
+	i := pr.next
+	pr.next++
+
+	str := func(name string) string {
+		if values := pr.columns[name]; i < len(values) {
+			s, _ := values[i].(string)
+			return s
+		}
+		return ""
+	}
+	i32 := func(name string) int32 {
+		if values := pr.columns[name]; i < len(values) {
+			n, _ := values[i].(int32)
+			return n
+		}
+		return 0
+	}
+	boolean := func(name string) bool {
+		if values := pr.columns[name]; i < len(values) {
+			b, _ := values[i].(bool)
+			return b
+		}
+		return false
+	}
+
+	r := &Record{
+		ID:       str("id"),
+		Channel:  str("channel"),
+		Provider: str("provider"),
+		Content:  str("content"),
+		Status:   str("status"),
+		Client:   str("client"),
+		Tenant:   str("tenant"),
+
+		Revision:        i32("revision"),
+		ContentType:     str("content_type"),
+		Template:        str("template"),
+		TemplateVersion: i32("template_version"),
+		Locale:          str("locale"),
+		Render:          boolean("render"),
+	}
+	if err := json.Unmarshal([]byte(str("history")), &r.History); err != nil {
+		return nil, err
+	}
+	if s := str("encrypted_content"); s != "" {
+		if err := json.Unmarshal([]byte(s), &r.EncryptedContent); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (pr *parquetReader) Close() error {
+	return nil
+}
@@ -0,0 +1,204 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/microapis/messages-core/tenant"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Sink is the destination of the archive files.
+type Sink interface {
+	// Create creates the file with the given name.
+	Create(name string) (io.WriteCloser, error)
+
+	// Open opens the file with the given name.
+	Open(name string) (io.ReadCloser, error)
+
+	// List returns the names of the files, sorted by name.
+	List() ([]string, error)
+
+	// For returns the sink of the files of tenant, kept under
+	// tenants/<id>, apart from the files of the other tenants.
+	For(tenantID string) (Sink, error)
+}
+
+// tenantDir is the directory of the files of the tenants.
+const tenantDir = "tenants"
+
+// NewSink returns the Sink described by rawurl, which is a local directory
+// path (optionally prefixed with file://) or an s3://bucket/prefix url.
+//
+// The s3 url accepts the endpoint and secure query params to use an S3
+// compatible server, the credentials are read from the AWS_ACCESS_KEY_ID
+// and AWS_SECRET_ACCESS_KEY environment variables.
+func NewSink(rawurl string) (Sink, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		return NewDirSink(u.Path)
+	case "s3":
+		endpoint := u.Query().Get("endpoint")
+		if endpoint == "" {
+			endpoint = "s3.amazonaws.com"
+		}
+		return NewS3Sink(endpoint, u.Query().Get("secure") != "false", u.Host, strings.TrimPrefix(u.Path, "/"))
+	}
+
+	return nil, fmt.Errorf("unknown archive sink scheme %q", u.Scheme)
+}
+
+// DirSink stores the files in a local directory.
+type DirSink struct {
+	Dir string
+}
+
+// NewDirSink ...
+func NewDirSink(dir string) (*DirSink, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	return &DirSink{
+		Dir: dir,
+	}, nil
+}
+
+// Create ...
+func (s *DirSink) Create(name string) (io.WriteCloser, error) {
+	return os.OpenFile(filepath.Join(s.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+}
+
+// Open ...
+func (s *DirSink) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.Dir, name))
+}
+
+// For returns the sink of the subdirectory of tenant, creating it if it
+// does not exist. The default tenant keeps its files on s.
+func (s *DirSink) For(tenantID string) (Sink, error) {
+	if tenantID == tenant.Default {
+		return s, nil
+	}
+
+	return NewDirSink(filepath.Join(s.Dir, tenantDir, tenantID))
+}
+
+// List ...
+func (s *DirSink) List() ([]string, error) {
+	ff, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ff))
+	for _, f := range ff {
+		if !f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// S3Sink stores the files in a bucket of an S3 compatible server.
+type S3Sink struct {
+	Client *minio.Client
+	Bucket string
+	Prefix string
+}
+
+// NewS3Sink ...
+func NewS3Sink(endpoint string, secure bool, bucket, prefix string) (*S3Sink, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewEnvAWS(),
+		Secure: secure,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Sink{
+		Client: client,
+		Bucket: bucket,
+		Prefix: prefix,
+	}, nil
+}
+
+// Create uploads the file while it is written, the upload finishes when
+// the returned writer is closed.
+func (s *S3Sink) Create(name string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := s.Client.PutObject(context.Background(), s.Bucket, path.Join(s.Prefix, name), pr, -1, minio.PutObjectOptions{})
+		pr.CloseWithError(err)
+		done <- err
+	}()
+
+	return &s3Writer{pw, done}, nil
+}
+
+// Open ...
+func (s *S3Sink) Open(name string) (io.ReadCloser, error) {
+	return s.Client.GetObject(context.Background(), s.Bucket, path.Join(s.Prefix, name), minio.GetObjectOptions{})
+}
+
+// For returns the sink of the prefix of tenant. The default tenant keeps
+// its files on s.
+func (s *S3Sink) For(tenantID string) (Sink, error) {
+	if tenantID == tenant.Default {
+		return s, nil
+	}
+
+	return &S3Sink{
+		Client: s.Client,
+		Bucket: s.Bucket,
+		Prefix: path.Join(s.Prefix, tenantDir, tenantID),
+	}, nil
+}
+
+// List ...
+func (s *S3Sink) List() ([]string, error) {
+	prefix := s.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	names := make([]string, 0)
+	for obj := range s.Client.ListObjects(context.Background(), s.Bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		names = append(names, strings.TrimPrefix(obj.Key, prefix))
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+type s3Writer struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Close() error {
+	w.PipeWriter.Close()
+	return <-w.done
+}
@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/microapis/messages-core/health"
+	"github.com/microapis/messages-core/message"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Permissions ...
+const (
+	// ReadAll allows to get the messages put by other clients.
+	ReadAll = "read_all"
+	// CancelAll allows to update and cancel the messages put by other
+	// clients.
+	CancelAll = "cancel_all"
+	// Templates allows to create and roll back the templates of the
+	// channels the client may send on.
+	Templates = "templates"
+	// Admin allows to export and backup the messages.
+	Admin = "admin"
+)
+
+// Metadata keys of the credentials.
+const (
+	APIKeyHeader        = "x-api-key"
+	AuthorizationHeader = "authorization"
+)
+
+// Client is an authenticated caller of the service.
+type Client struct {
+	ID string `yaml:"id" toml:"id" json:"id"`
+
+	// Tenant is the only tenant the client may act on, empty for the
+	// default tenant.
+	Tenant string `yaml:"tenant" toml:"tenant" json:"tenant"`
+
+	// Channels are the channels the client may send on, "*" allows every
+	// channel.
+	Channels []string `yaml:"channels" toml:"channels" json:"channels"`
+
+	// Permissions are granted over the messages of other clients, every
+	// client may read, update and cancel its own messages.
+	Permissions []string `yaml:"permissions" toml:"permissions" json:"permissions"`
+}
+
+// Can reports whether the client has the permission.
+func (c *Client) Can(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission || p == Admin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanSend reports whether the client may send on channel.
+func (c *Client) CanSend(channel string) bool {
+	for _, ch := range c.Channels {
+		if ch == "*" || ch == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanRead reports whether the client may get m.
+func (c *Client) CanRead(m *message.Message) bool {
+	return m.Client == c.ID || c.Can(ReadAll)
+}
+
+// CanModify reports whether the client may update or cancel m.
+func (c *Client) CanModify(m *message.Message) bool {
+	return m.Client == c.ID || c.Can(CancelAll)
+}
+
+// APIKey is a static key of a client, sent on the x-api-key metadata.
+type APIKey struct {
+	Key    string `yaml:"key" toml:"key" json:"key"`
+	Client `yaml:",inline"`
+}
+
+// JWTConfig verifies the bearer tokens sent on the authorization
+// metadata. The subject of a token is the id of the client, and its
+// tenant, channels and permissions claims are the ones of the Client.
+type JWTConfig struct {
+	// Secret verifies HS256 tokens.
+	Secret string `yaml:"secret" toml:"secret" json:"secret"`
+
+	// PublicKeyFile is a PEM encoded RSA or ECDSA key that verifies RS256
+	// and ES256 tokens.
+	PublicKeyFile string `yaml:"public_key_file" toml:"public_key_file" json:"public_key_file"`
+
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	// The exp claim is always required.
+	Issuer   string `yaml:"issuer" toml:"issuer" json:"issuer"`
+	Audience string `yaml:"audience" toml:"audience" json:"audience"`
+}
+
+// Config ...
+type Config struct {
+	APIKeys []APIKey   `yaml:"api_keys" toml:"api_keys" json:"api_keys"`
+	JWT     *JWTConfig `yaml:"jwt" toml:"jwt" json:"jwt"`
+}
+
+// Enabled reports whether the callers must be authenticated.
+func (c Config) Enabled() bool {
+	return len(c.APIKeys) > 0 || c.JWT != nil
+}
+
+// Validate ...
+func (c Config) Validate() error {
+	keys := make(map[string]bool)
+	for i, k := range c.APIKeys {
+		if k.Key == "" {
+			return fmt.Errorf("api_keys[%d].key is required", i)
+		}
+		if k.ID == "" {
+			return fmt.Errorf("api_keys[%d].id is required", i)
+		}
+		if keys[k.Key] {
+			return fmt.Errorf("api_keys[%d].key is repeated", i)
+		}
+		keys[k.Key] = true
+	}
+
+	if c.JWT != nil && (c.JWT.Secret == "") == (c.JWT.PublicKeyFile == "") {
+		return errors.New("jwt requires one of secret or public_key_file")
+	}
+
+	return nil
+}
+
+// Authenticator authenticates the callers of a gRPC server.
+type Authenticator struct {
+	keys []APIKey
+
+	jwt    *JWTConfig
+	keyFn  jwt.Keyfunc
+	parser *jwt.Parser
+}
+
+// New ...
+func New(c Config) (*Authenticator, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		keys: c.APIKeys,
+		jwt:  c.JWT,
+	}
+
+	if c.JWT != nil {
+		methods, key, err := c.JWT.key()
+		if err != nil {
+			return nil, err
+		}
+		a.keyFn = func(*jwt.Token) (interface{}, error) { return key, nil }
+		a.parser = jwt.NewParser(jwt.WithValidMethods(methods))
+	}
+
+	return a, nil
+}
+
+// key returns the signing methods and the key that verifies the tokens.
+func (c *JWTConfig) key() ([]string, interface{}, error) {
+	if c.Secret != "" {
+		return []string{"HS256"}, []byte(c.Secret), nil
+	}
+
+	b, err := ioutil.ReadFile(c.PublicKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(b); err == nil {
+		return []string{"RS256"}, key, nil
+	}
+	key, err := jwt.ParseECPublicKeyFromPEM(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: not a RSA or ECDSA public key", c.PublicKeyFile)
+	}
+
+	return []string{"ES256"}, key, nil
+}
+
+// claims are the claims of the bearer tokens.
+type claims struct {
+	Tenant      string   `json:"tenant"`
+	Channels    []string `json:"channels"`
+	Permissions []string `json:"permissions"`
+	jwt.RegisteredClaims
+}
+
+// Authenticate returns the client of the credentials on the incoming
+// metadata of ctx.
+func (a *Authenticator) Authenticate(ctx context.Context) (*Client, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if v := md.Get(APIKeyHeader); len(v) > 0 {
+		for i := range a.keys {
+			if subtle.ConstantTimeCompare([]byte(a.keys[i].Key), []byte(v[0])) == 1 {
+				return &a.keys[i].Client, nil
+			}
+		}
+		return nil, errors.New("invalid api key")
+	}
+
+	if v := md.Get(AuthorizationHeader); len(v) > 0 && a.parser != nil {
+		raw := strings.TrimPrefix(v[0], "Bearer ")
+		if raw == v[0] {
+			return nil, errors.New("authorization must be a bearer token")
+		}
+
+		// the parser verifies exp only when it is set, so the tokens
+		// that never expire are rejected here.
+		var cl claims
+		if _, err := a.parser.ParseWithClaims(raw, &cl, a.keyFn); err != nil {
+			return nil, err
+		}
+		if cl.ExpiresAt == nil {
+			return nil, errors.New("token without expiration")
+		}
+		if a.jwt.Issuer != "" && !cl.VerifyIssuer(a.jwt.Issuer, true) {
+			return nil, errors.New("invalid token issuer")
+		}
+		if a.jwt.Audience != "" && !cl.VerifyAudience(a.jwt.Audience, true) {
+			return nil, errors.New("invalid token audience")
+		}
+		if cl.Subject == "" {
+			return nil, errors.New("token without subject")
+		}
+
+		return &Client{
+			ID:          cl.Subject,
+			Tenant:      cl.Tenant,
+			Channels:    cl.Channels,
+			Permissions: cl.Permissions,
+		}, nil
+	}
+
+	return nil, errors.New("missing credentials")
+}
+
+// UnaryInterceptor rejects the unauthenticated requests with
+// Unauthenticated and adds the client to the context of the others. The
+// health checks are not authenticated, so the probes do not need keys.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if health.IsHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		c, err := a.Authenticate(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(NewContext(ctx, c), req)
+	}
+}
+
+// StreamInterceptor is the UnaryInterceptor of the streams.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if health.IsHealthMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		c, err := a.Authenticate(ss.Context())
+		if err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(srv, &serverStream{ss, NewContext(ss.Context(), c)})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying c.
+func NewContext(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the client carried by ctx, it is not found when the
+// authentication is disabled.
+func FromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(contextKey{}).(*Client)
+	return c, ok
+}
+
+// Authorize returns PermissionDenied when the client of ctx is not
+// allowed by allow, and nil when the authentication is disabled.
+func Authorize(ctx context.Context, allow func(c *Client) bool) error {
+	c, ok := FromContext(ctx)
+	if !ok || allow(c) {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "client %q is not allowed", c.ID)
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/metadata"
+)
+
+const secret = "secret"
+
+func token(t *testing.T, cl claims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	a, err := New(Config{JWT: &JWTConfig{Secret: secret, Issuer: "issuer", Audience: "messages"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() claims {
+		return claims{RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "client",
+			Issuer:    "issuer",
+			Audience:  jwt.ClaimStrings{"messages"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		claim func(cl *claims)
+		ok    bool
+	}{
+		{"valid", func(*claims) {}, true},
+		{"without expiration", func(cl *claims) { cl.ExpiresAt = nil }, false},
+		{"expired", func(cl *claims) { cl.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }, false},
+		{"other issuer", func(cl *claims) { cl.Issuer = "other" }, false},
+		{"without issuer", func(cl *claims) { cl.Issuer = "" }, false},
+		{"other audience", func(cl *claims) { cl.Audience = jwt.ClaimStrings{"other"} }, false},
+		{"without subject", func(cl *claims) { cl.Subject = "" }, false},
+	}
+
+	for _, tt := range tests {
+		cl := valid()
+		tt.claim(&cl)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+token(t, cl)))
+
+		c, err := a.Authenticate(ctx)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Authenticate() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+		if tt.ok && (c == nil || c.ID != "client") {
+			t.Errorf("%s: Authenticate() = %+v, want the client", tt.name, c)
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package backend
 
 import (
+	"encoding/json"
 	"log"
 	"net"
 
 	"github.com/microapis/messages-api"
 	"github.com/microapis/messages-api/proto"
+	"github.com/microapis/messages-core/tlsconfig"
+	"github.com/microapis/messages-core/tracing"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 )
 
 // Backend manages the approval and delivery of messages.
@@ -22,17 +28,59 @@ type Backend interface {
 	Deliver(content string) error
 }
 
+// ProviderBackend is a Backend that receives the provider of the message
+// and its params, with the secret references already resolved.
+type ProviderBackend interface {
+	DeliverProvider(content, provider string, params map[string]string) error
+}
+
+// Metadata keys of the provider sent with the deliveries.
+const (
+	ProviderHeader = "x-provider"
+	// ParamsHeader is binary, so the values are not restricted to ASCII.
+	ParamsHeader = "x-provider-params-bin"
+)
+
 // ListenAndServe ...
 func ListenAndServe(addr string, backend messages.Backend) error {
+	return ListenAndServeTLS(addr, backend, tlsconfig.Config{})
+}
+
+// ListenAndServeTLS is like ListenAndServe but serves over TLS when the
+// config is enabled. With config.ClientAuth the scheduler must present
+// a certificate signed by config.CAFile, optionally restricted to
+// config.AllowedNames.
+func ListenAndServeTLS(addr string, backend messages.Backend, config tlsconfig.Config) error {
+	opts := []grpc.ServerOption{}
+	if config.Enabled() {
+		if err := config.Validate(); err != nil {
+			return err
+		}
+
+		creds, err := config.ServerCredentials()
+		if err != nil {
+			return err
+		}
+		defer creds.Close()
+		opts = append(opts, grpc.Creds(creds))
+	}
+
 	lis, err := net.Listen("tcp", string(addr))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	s := grpc.NewServer()
+	opts = append(opts, grpc.UnaryInterceptor(tracing.UnaryServerInterceptor()))
+	s := grpc.NewServer(opts...)
 
 	proto.RegisterMessageBackendServiceServer(s, &service{backend})
 
+	// the backend serves while the process is running, the scheduler
+	// checks it to report the health of the channel.
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
+
 	return s.Serve(lis)
 }
 
@@ -56,7 +104,23 @@ func (s *service) Approve(ctx context.Context, r *proto.MessageBackendApproveReq
 
 func (s *service) Deliver(ctx context.Context, r *proto.MessageBackendDeliverRequest) (*proto.MessageBackendDeliverResponse, error) {
 	var resp proto.MessageBackendDeliverResponse
-	if err := s.backend.Deliver(r.Content); err != nil {
+
+	deliver := s.backend.Deliver
+	md, _ := metadata.FromIncomingContext(ctx)
+	if pb, ok := s.backend.(ProviderBackend); ok && len(md.Get(ProviderHeader)) > 0 {
+		var params map[string]string
+		if v := md.Get(ParamsHeader); len(v) > 0 {
+			if err := json.Unmarshal([]byte(v[0]), &params); err != nil {
+				return nil, err
+			}
+		}
+		provider := md.Get(ProviderHeader)[0]
+		deliver = func(content string) error {
+			return pb.DeliverProvider(content, provider, params)
+		}
+	}
+
+	if err := deliver(r.Content); err != nil {
 		resp.Error = &proto.MessagesError{
 			Code:    500,
 			Message: err.Error(),
@@ -0,0 +1,202 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/microapis/messages-api/proto"
+	"github.com/microapis/messages-core/tlsconfig"
+	"github.com/microapis/messages-core/tracing"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ Backend = (*Client)(nil)
+
+// RejectedError is returned by Approve when the backend rejects the
+// content, with the reason given by the backend.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "content rejected: " + e.Reason
+}
+
+// Client calls a backend served by ListenAndServe.
+type Client struct {
+	conn   *grpc.ClientConn
+	client proto.MessageBackendServiceClient
+}
+
+// Approve ...
+func (c *Client) Approve(content string) (bool, error) {
+	return c.ApproveContext(context.Background(), content)
+}
+
+// ApproveContext is like Approve but sends the trace context of ctx to
+// the backend.
+func (c *Client) ApproveContext(ctx context.Context, content string) (bool, error) {
+	resp, err := c.client.Approve(ctx, &proto.MessageBackendApproveRequest{
+		Content: content,
+	})
+	if err != nil {
+		return false, err
+	}
+	if e := resp.GetError(); e != nil {
+		return false, &RejectedError{Reason: e.GetMessage()}
+	}
+
+	return resp.GetValid(), nil
+}
+
+// Deliver ...
+func (c *Client) Deliver(content string) error {
+	return c.DeliverContext(context.Background(), content)
+}
+
+// DeliverContext is like Deliver but sends the trace context of ctx to
+// the backend.
+func (c *Client) DeliverContext(ctx context.Context, content string) error {
+	return c.deliver(ctx, content)
+}
+
+// DeliverProvider delivers the content with the given provider and its
+// resolved params, which are sent on the metadata of the call so they
+// are never stored by the backend.
+func (c *Client) DeliverProvider(content, provider string, params map[string]string) error {
+	return c.DeliverProviderContext(context.Background(), content, provider, params)
+}
+
+// DeliverProviderContext is like DeliverProvider but sends the trace
+// context of ctx to the backend.
+func (c *Client) DeliverProviderContext(ctx context.Context, content, provider string, params map[string]string) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		ProviderHeader, provider,
+		ParamsHeader, string(b),
+	)
+
+	return c.deliver(ctx, content)
+}
+
+func (c *Client) deliver(ctx context.Context, content string) error {
+	resp, err := c.client.Deliver(ctx, &proto.MessageBackendDeliverRequest{
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+	if e := resp.GetError(); e != nil {
+		return errors.New(e.GetMessage())
+	}
+
+	return nil
+}
+
+// Check reports whether the backend is serving, with the grpc.health.v1
+// service registered by ListenAndServe.
+func (c *Client) Check(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if s := resp.GetStatus(); s != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("backend is %s", s)
+	}
+
+	return nil
+}
+
+// Close ...
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Clients keeps a Client per backend address.
+type Clients struct {
+	creds *tlsconfig.Credentials
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewClients returns the clients of the backends, which are dialed over
+// TLS when the config is enabled. The backend certificates are verified
+// against config.CAFile and the host of their address, and with a client
+// certificate configured the scheduler identifies itself with it.
+func NewClients(config tlsconfig.Config) (*Clients, error) {
+	cc := &Clients{
+		clients: make(map[string]*Client),
+	}
+
+	if config.Enabled() {
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+
+		creds, err := config.ClientCredentials("")
+		if err != nil {
+			return nil, err
+		}
+		cc.creds = creds
+	}
+
+	return cc, nil
+}
+
+// Close closes the clients and stops reloading the TLS files.
+func (cc *Clients) Close() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var err error
+	for addr, c := range cc.clients {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(cc.clients, addr)
+	}
+	if cc.creds != nil {
+		cc.creds.Close()
+	}
+
+	return err
+}
+
+// Get returns the client of the backend on addr, dialing it the first
+// time.
+func (cc *Clients) Get(addr string) (*Client, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if c, ok := cc.clients[addr]; ok {
+		return c, nil
+	}
+
+	opt := grpc.WithInsecure()
+	if cc.creds != nil {
+		opt = grpc.WithTransportCredentials(cc.creds)
+	}
+
+	conn, err := grpc.Dial(addr, opt, grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:   conn,
+		client: proto.NewMessageBackendServiceClient(conn),
+	}
+	cc.clients[addr] = c
+
+	return c, nil
+}
@@ -0,0 +1,324 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/microapis/messages-core/channel"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/oklog/ulid"
+
+	channelRedis "github.com/microapis/messages-core/channel/database/redis"
+	messagedb "github.com/microapis/messages-core/message/database"
+)
+
+// Names of the files inside a backup.
+const (
+	// DBFile is the snapshot of the messages db.
+	DBFile = "messages.db"
+	// QueueFile is the list of ids waiting in the priority queue.
+	QueueFile = "queue.json"
+	// ChannelsFile is the channel registry.
+	ChannelsFile = "channels.json"
+
+	// TenantsDir keeps a directory per tenant, other than the default one,
+	// with its QueueFile and ChannelsFile.
+	TenantsDir = "tenants"
+)
+
+// Write writes a gzip compressed tar with a consistent snapshot of the
+// messages db, the ids of the priority queue and the registered channels.
+//
+// The snapshot is taken inside a read transaction, so the service keeps
+// working while the backup is written. The queues are listed inside the
+// same transaction and the ids of the messages pushed after it began are
+// dropped, as they are not on the snapshot. The approved messages popped
+// meanwhile are pushed again when the service resumes after a restore.
+//
+// cs may be nil when redis is not configured. The queues and channels of
+// tenants are written on their directory inside TenantsDir.
+func Write(w io.Writer, dst *messagedb.BoltDatastore, pq queue.Queue, cs *channelRedis.ChannelStore, tenants []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	err := dst.DB.View(func(tx *bolt.Tx) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    DBFile,
+			Mode:    0600,
+			Size:    tx.Size(),
+			ModTime: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.WriteTo(tw); err != nil {
+			return err
+		}
+
+		if err := writeTenant(tw, tx, tenant.Default, pq, cs, now); err != nil {
+			return err
+		}
+
+		for _, t := range tenants {
+			tpq, err := pq.For(t)
+			if err != nil {
+				return err
+			}
+			var tcs *channelRedis.ChannelStore
+			if cs != nil {
+				tcs = cs.For(t)
+			}
+			if err := writeTenant(tw, tx, t, tpq, tcs, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// writeTenant writes the queue and the channels of tenantID on its
+// directory, keeping the ids of the messages stored on the snapshot of tx.
+func writeTenant(tw *tar.Writer, tx *bolt.Tx, tenantID string, pq queue.Queue, cs *channelRedis.ChannelStore, now time.Time) error {
+	dir := ""
+	if tenantID != tenant.Default {
+		dir = path.Join(TenantsDir, tenantID)
+	}
+
+	all, err := pq.List()
+	if err != nil {
+		return err
+	}
+	ids := make([]ulid.ULID, 0, len(all))
+	if b := messagedb.Bucket(tx, tenantID, messagedb.MsgBucket); b != nil {
+		for _, id := range all {
+			if b.Get(id[:]) != nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if err := writeJSON(tw, path.Join(dir, QueueFile), ids, now); err != nil {
+		return err
+	}
+
+	cc := make([]*channel.Channel, 0)
+	if cs != nil {
+		if cc, err = cs.GetAll(); err != nil {
+			return err
+		}
+		// the params are kept encrypted on the backup.
+		for _, c := range cc {
+			if err := cs.Seal(c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeJSON(tw, path.Join(dir, ChannelsFile), cc, now)
+}
+
+func writeJSON(tw *tar.Writer, name string, v interface{}, now time.Time) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(b)),
+		ModTime: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(b)
+	return err
+}
+
+// Restore rebuilds the messages db on dbPath, the priority queue and the
+// channel registry from a backup written by Write.
+//
+// The messages db is replaced, so the service must be stopped. The queue
+// and the channels of each tenant on the backup are cleared before
+// restoring them. pq may be nil to skip the queue, as when the queue is
+// kept in the messages db, and cs may be nil to skip the channels.
+//
+// Only the file names written by Write are restored, any other entry
+// fails the restore.
+func Restore(r io.Reader, dbPath string, pq queue.Queue, cs *channelRedis.ChannelStore) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if h.Name == DBFile {
+			if err := restoreDB(tr, dbPath); err != nil {
+				return fmt.Errorf("%s: %v", h.Name, err)
+			}
+			continue
+		}
+
+		// the files of a tenant are restored on its queue and channels.
+		t, name, ok := split(h.Name)
+		if !ok {
+			return fmt.Errorf("unknown backup file %s", h.Name)
+		}
+
+		switch name {
+		case QueueFile:
+			if pq == nil {
+				continue
+			}
+			tpq := pq
+			if t != tenant.Default {
+				if tpq, err = pq.For(t); err != nil {
+					return err
+				}
+			}
+			err = restoreQueue(tr, tpq)
+		case ChannelsFile:
+			if cs == nil {
+				continue
+			}
+			tcs := cs
+			if t != tenant.Default {
+				tcs = cs.For(t)
+			}
+			err = restoreChannels(tr, tcs)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", h.Name, err)
+		}
+	}
+}
+
+// split returns the tenant and the name of a queue or channels file of a
+// backup, reporting whether name is one of them.
+func split(name string) (string, string, bool) {
+	t, base := tenant.Default, name
+	if strings.HasPrefix(name, TenantsDir+"/") {
+		parts := strings.Split(strings.TrimPrefix(name, TenantsDir+"/"), "/")
+		if len(parts) != 2 || !validTenant(parts[0]) {
+			return "", "", false
+		}
+		t, base = parts[0], parts[1]
+	}
+
+	if base != QueueFile && base != ChannelsFile {
+		return "", "", false
+	}
+
+	return t, base, true
+}
+
+// validTenant reports whether t can be the directory of a tenant.
+func validTenant(t string) bool {
+	return t != "" && t != "." && t != ".." && !tenant.Namespaced(t)
+}
+
+// restoreQueue replaces the ids of pq with the ones on r.
+func restoreQueue(r io.Reader, pq queue.Queue) error {
+	var ids []ulid.ULID
+	if err := json.NewDecoder(r).Decode(&ids); err != nil {
+		return err
+	}
+
+	current, err := pq.List()
+	if err != nil {
+		return err
+	}
+	for _, id := range current {
+		if _, err := pq.Delete(id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range ids {
+		if err := pq.Push(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreChannels replaces the channels of cs with the ones on r.
+func restoreChannels(r io.Reader, cs *channelRedis.ChannelStore) error {
+	var cc []*channel.Channel
+	if err := json.NewDecoder(r).Decode(&cc); err != nil {
+		return err
+	}
+
+	current, err := cs.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, c := range current {
+		if err := cs.Delete(c.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range cc {
+		if err := cs.Register(*c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreDB writes the snapshot next to dbPath and renames it, so a
+// failed restore does not leave a broken db.
+func restoreDB(r io.Reader, dbPath string) error {
+	f, err := ioutil.TempFile(filepath.Dir(dbPath), ".messages-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), dbPath)
+}
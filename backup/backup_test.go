@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/message"
+	"github.com/oklog/ulid"
+
+	messagedb "github.com/microapis/messages-core/message/database"
+	messageBolt "github.com/microapis/messages-core/message/database/bolt"
+	queueBolt "github.com/microapis/messages-core/queue/bolt"
+)
+
+func newID(t *testing.T, at time.Time) ulid.ULID {
+	t.Helper()
+	return ulid.MustNew(ulid.Timestamp(at), rand.Reader)
+}
+
+func newQueue(t *testing.T, path string) *queueBolt.Queue {
+	t.Helper()
+	dst, err := messagedb.NewBoltDatastore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dst.DB.Close() })
+
+	pq, err := queueBolt.NewQueue(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pq
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	dst, err := messagedb.NewBoltDatastore(filepath.Join(dir, "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.DB.Close()
+	ms, err := messageBolt.NewMessageStore(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	stored := newID(t, now)
+	if err := ms.AddMessage(message.Message{ID: stored, Channel: "email", Content: "hello"}, message.SourceAPI); err != nil {
+		t.Fatal(err)
+	}
+
+	// unstored is queued but not on the snapshot, so it is dropped.
+	unstored := newID(t, now.Add(time.Second))
+	pq := newQueue(t, filepath.Join(dir, "queue.db"))
+	for _, id := range []ulid.ULID{stored, unstored} {
+		if err := pq.Push(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, dst, pq, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the ids already on the target queue are replaced.
+	target := newQueue(t, filepath.Join(dir, "target-queue.db"))
+	if err := target.Push(newID(t, now.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	dbPath := filepath.Join(dir, "restored.db")
+	if err := Restore(&buf, dbPath, target, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := target.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ulid.ULID{stored}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("queue = %v, want %v", ids, want)
+	}
+
+	rdst, err := messagedb.NewBoltDatastore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdst.DB.Close()
+	rms, err := messageBolt.NewMessageStore(rdst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rms.Get(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Content != "hello" {
+		t.Errorf("content = %q, want %q", m.Content, "hello")
+	}
+}
+
+func TestRestoreUnknownFiles(t *testing.T) {
+	tests := []string{
+		"tenants/acme/messages.db",
+		"tenants/acme/other/queue.json",
+		"tenants/../queue.json",
+		"tenants/a:b/channels.json",
+		"tenants//queue.json",
+		"../messages.db",
+		"notes.txt",
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			if err := writeJSON(tw, name, []ulid.ULID{}, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			tw.Close()
+			gz.Close()
+
+			dbPath := filepath.Join(t.TempDir(), "messages.db")
+			if err := Restore(&buf, dbPath, nil, nil); err == nil {
+				t.Fatalf("Restore(%s) = nil, want error", name)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package channel
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
+
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/secret"
 )
 
 // Channel ...
@@ -10,6 +15,42 @@ type Channel struct {
 	Host      string      `json:"host"`
 	Port      string      `json:"port"`
 	Providers []*Provider `json:"providers"`
+
+	// Tenant owns the channel, empty for the default tenant.
+	Tenant string `json:"tenant,omitempty"`
+
+	// Schema of the content of the messages, nil accepts any content.
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// ErrInvalidName is returned for the names that cannot be stored as a
+// channel.
+var ErrInvalidName = errors.New("channel name cannot contain \":\"")
+
+// CheckName fails with ErrInvalidName when name contains ":", which
+// namespaces the keys of the tenants, so a channel of the default tenant
+// cannot be read as one of another tenant.
+func CheckName(name string) error {
+	if strings.Contains(name, ":") {
+		return ErrInvalidName
+	}
+
+	return nil
+}
+
+// Schema describes the content accepted by a channel, which is validated
+// by the core before the approval of the backend. A schema with JSON
+// accepts JSON content, and one with a Descriptor protobuf content.
+type Schema struct {
+	// JSON is a JSON Schema document.
+	JSON json.RawMessage `json:"json,omitempty"`
+
+	// Descriptor is a serialized google.protobuf.FileDescriptorSet with
+	// the Message of the content and its dependencies.
+	Descriptor []byte `json:"descriptor,omitempty"`
+
+	// Message is the full name of the protobuf message of the content.
+	Message string `json:"message,omitempty"`
 }
 
 // Address Get an provider address
@@ -27,8 +68,42 @@ func (p *Channel) ProvidersNames() string {
 	return strings.Join(names, ",")
 }
 
+// Provider returns the provider with the given name, nil if not found.
+func (p *Channel) Provider(name string) *Provider {
+	for _, v := range p.Providers {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the channel without the secret values of
+// the params, to be logged or sent to the clients.
+func (p *Channel) Redacted() *Channel {
+	c := *p
+	c.Providers = make([]*Provider, 0, len(p.Providers))
+	for _, v := range p.Providers {
+		c.Providers = append(c.Providers, &Provider{
+			Name:   v.Name,
+			Params: secret.Redact(v.Params),
+		})
+	}
+
+	return &c
+}
+
 // Provider ...
 type Provider struct {
-	Name   string            `json:"name"`
+	Name string `json:"name"`
+
+	// Params configure the provider, their values may be secret
+	// references like secret://sendgrid/api_key, which are resolved only
+	// when a message is delivered.
 	Params map[string]string `json:"params"`
+
+	// EncryptedParams replaces Params at rest when the encryption is
+	// enabled.
+	EncryptedParams *encryption.Envelope `json:"encrypted_params,omitempty"`
 }
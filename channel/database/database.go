@@ -12,8 +12,9 @@ type RedisDatastore struct {
 }
 
 // NewRedisDatastore returns a new datastore instance or an error if
-// a datasore cannot be returned
-func NewRedisDatastore(url string) (*RedisDatastore, error) {
+// a datasore cannot be returned. A poolSize of 0 uses the default size
+// of the client.
+func NewRedisDatastore(url string, poolSize int) (*RedisDatastore, error) {
 	opts, err := redis.ParseURL(url)
 	if err != nil {
 		return nil, err
@@ -22,6 +23,10 @@ func NewRedisDatastore(url string) (*RedisDatastore, error) {
 	// see: https://github.com/go-redis/redis/issues/1343
 	opts.Username = ""
 
+	if poolSize > 0 {
+		opts.PoolSize = poolSize
+	}
+
 	client := redis.NewClient(opts)
 	ctx := context.Background()
 
@@ -34,3 +39,9 @@ func NewRedisDatastore(url string) (*RedisDatastore, error) {
 		Client: client,
 	}, nil
 }
+
+// Ping reports whether redis is reachable, the connections are restored
+// by the client after an outage.
+func (dst *RedisDatastore) Ping(ctx context.Context) error {
+	return dst.Client.Ping(ctx).Err()
+}
@@ -3,15 +3,31 @@ package redis
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 
+	"github.com/go-redis/redis"
 	"github.com/microapis/messages-core/channel"
+	"github.com/microapis/messages-core/content"
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/store"
+	"github.com/microapis/messages-core/tenant"
+
 	db "github.com/microapis/messages-core/channel/database"
 )
 
 // ChannelStore ...
 type ChannelStore struct {
 	Dst *db.RedisDatastore
+
+	// Tenant owns the channels of the store, the keys of the other tenants
+	// are prefixed with their id.
+	Tenant string
+
+	// Encrypter encrypts the params of the providers at rest, nil stores
+	// them in plaintext.
+	Encrypter *encryption.Encrypter
 }
 
 // NewChannelStore ...
@@ -21,19 +37,56 @@ func NewChannelStore(dst *db.RedisDatastore) (*ChannelStore, error) {
 	}, nil
 }
 
-// Register ...
+// For returns the store of the channels of tenant.
+func (ss *ChannelStore) For(tenantID string) *ChannelStore {
+	return &ChannelStore{
+		Dst:       ss.Dst,
+		Tenant:    tenantID,
+		Encrypter: ss.Encrypter,
+	}
+}
+
+// Register stores c, replacing the channel with the same name. It fails
+// with store.ErrConflict if the name is taken by a key that is not a
+// channel, as the priority queue, so it is never overwritten, and when
+// the schema of c is not valid. It fails with channel.ErrInvalidName when
+// the name of c contains ":".
 func (ss *ChannelStore) Register(c channel.Channel) error {
 	// TODO(ca): should get redis c.name value and also merge c.Providers and cc.Providers
 
+	if err := channel.CheckName(c.Name); err != nil {
+		return fmt.Errorf("channel %s: %w", c.Name, err)
+	}
+
+	ctx := context.Background()
+	key := tenant.Key(ss.Tenant, c.Name)
+	typ, err := ss.Dst.Client.Type(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if typ != "none" && typ != "string" {
+		return fmt.Errorf("channel %s: key holds a %s: %w", c.Name, typ, store.ErrConflict)
+	}
+
+	if c.Schema != nil {
+		if _, err := content.Compile(c.Schema); err != nil {
+			return fmt.Errorf("channel %s: schema: %v", c.Name, err)
+		}
+	}
+
+	c.Tenant = ss.Tenant
+	if err := ss.Seal(&c); err != nil {
+		return err
+	}
+
 	b, err := json.Marshal(c)
 	if err != nil {
 		return err
 	}
 
-	log.Println("ChannelStore#Register", c.Name, string(b))
+	slog.Debug("channel registered", "channel", c.Name, "tenant", ss.Tenant, "providers", c.ProvidersNames())
 
-	ctx := context.Background()
-	err = ss.Dst.Client.Set(ctx, c.Name, string(b), 0).Err()
+	err = ss.Dst.Client.Set(ctx, key, string(b), 0).Err()
 	if err != nil {
 		return err
 	}
@@ -41,53 +94,208 @@ func (ss *ChannelStore) Register(c channel.Channel) error {
 	return nil
 }
 
-// Get ...
+// Get returns the channel with the given name, failing with
+// store.ErrNotFound if it does not exist.
 func (ss *ChannelStore) Get(name string) (*channel.Channel, error) {
+	if channel.CheckName(name) != nil {
+		return nil, fmt.Errorf("channel %s: %w", name, store.ErrNotFound)
+	}
+
 	ctx := context.Background()
-	val, err := ss.Dst.Client.Get(ctx, name).Result()
+	val, err := ss.Dst.Client.Get(ctx, tenant.Key(ss.Tenant, name)).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("channel %s: %w", name, store.ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	log.Println("ChannelStore#Get", name, val)
-
 	c := &channel.Channel{}
 	err = json.Unmarshal([]byte(val), c)
 	if err != nil {
 		return nil, err
 	}
+	if err := ss.Open(c); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("channel loaded", "channel", name, "tenant", ss.Tenant, "providers", c.ProvidersNames())
 
 	return c, nil
 }
 
+// Delete removes the channel with the given name, failing with
+// store.ErrNotFound if it does not exist.
+func (ss *ChannelStore) Delete(name string) error {
+	if channel.CheckName(name) != nil {
+		return fmt.Errorf("channel %s: %w", name, store.ErrNotFound)
+	}
+
+	ctx := context.Background()
+	n, err := ss.Dst.Client.Del(ctx, tenant.Key(ss.Tenant, name)).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("channel %s: %w", name, store.ErrNotFound)
+	}
+
+	slog.Debug("channel deleted", "channel", name, "tenant", ss.Tenant)
+
+	return nil
+}
+
 // GetAll ...
 func (ss *ChannelStore) GetAll() ([]*channel.Channel, error) {
 	ctx := context.Background()
-	keys, err := ss.Dst.Client.Keys(ctx, "*").Result()
-	if err == nil {
+	all, err := ss.Dst.Client.Keys(ctx, tenant.Key(ss.Tenant, "*")).Result()
+	if err != nil {
 		return nil, err
 	}
 
-	log.Println("keys", keys)
-
-	values, err := ss.Dst.Client.MGet(ctx, keys...).Result()
-	if err == nil {
-		return nil, err
+	// the keys of the other tenants are skipped on the default one.
+	keys := all[:0]
+	for _, k := range all {
+		if ss.Tenant != tenant.Default || !tenant.Namespaced(k) {
+			keys = append(keys, k)
+		}
 	}
 
-	log.Println("value", values)
+	slog.Debug("channels listed", "tenant", ss.Tenant, "keys", keys)
 
 	cc := make([]*channel.Channel, 0)
+	if len(keys) == 0 {
+		return cc, nil
+	}
+
+	values, err := ss.Dst.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, v := range values {
+		// keys that are not strings, like the priority queue, are nil.
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+
 		c := &channel.Channel{}
-		err = json.Unmarshal([]byte(v.(string)), c)
+		err = json.Unmarshal([]byte(str), c)
 		if err != nil {
 			return nil, err
 		}
+		if err := ss.Open(c); err != nil {
+			return nil, err
+		}
 
 		cc = append(cc, c)
 	}
 
 	return cc, nil
 }
+
+// Rotate encrypts again the params of the channels that were not
+// encrypted with the current key or were kept in plaintext, and returns
+// the number of rewritten channels.
+func (ss *ChannelStore) Rotate() (int, error) {
+	if ss.Encrypter == nil {
+		return 0, errors.New("encryption is not configured")
+	}
+
+	cc, err := ss.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	// GetAll opens the params, so the stale ones are checked on the raw
+	// values.
+	var n int
+	for _, c := range cc {
+		val, err := ss.Dst.Client.Get(context.Background(), tenant.Key(ss.Tenant, c.Name)).Result()
+		if err != nil {
+			return n, err
+		}
+		raw := &channel.Channel{}
+		if err := json.Unmarshal([]byte(val), raw); err != nil {
+			return n, err
+		}
+		if !ss.stale(raw) {
+			continue
+		}
+
+		if err := ss.Register(*c); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// stale reports whether any provider of c must be encrypted again.
+func (ss *ChannelStore) stale(c *channel.Channel) bool {
+	for _, p := range c.Providers {
+		if len(p.Params) > 0 || (p.EncryptedParams != nil && ss.Encrypter.Stale(p.EncryptedParams)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// params identifies the params of the provider p of c, which their
+// encrypted params are bound to.
+func (ss *ChannelStore) params(c *channel.Channel, p *channel.Provider) []byte {
+	return []byte(tenant.Key(ss.Tenant, c.Name) + "/" + p.Name)
+}
+
+// Seal moves the params of the providers of c to their encrypted params
+// when the encryption is enabled, bound to the channel and the provider.
+func (ss *ChannelStore) Seal(c *channel.Channel) error {
+	if ss.Encrypter == nil {
+		return nil
+	}
+
+	providers := make([]*channel.Provider, 0, len(c.Providers))
+	for _, p := range c.Providers {
+		pp := *p
+		if len(pp.Params) > 0 {
+			b, err := json.Marshal(pp.Params)
+			if err != nil {
+				return err
+			}
+			if pp.EncryptedParams, err = ss.Encrypter.Encrypt(b, ss.params(c, p)); err != nil {
+				return err
+			}
+			pp.Params = nil
+		}
+		providers = append(providers, &pp)
+	}
+	c.Providers = providers
+
+	return nil
+}
+
+// Open decrypts the encrypted params of the providers of c.
+func (ss *ChannelStore) Open(c *channel.Channel) error {
+	for _, p := range c.Providers {
+		if p.EncryptedParams == nil {
+			continue
+		}
+		if ss.Encrypter == nil {
+			return fmt.Errorf("channel %s is encrypted and encryption is not configured", c.Name)
+		}
+
+		b, err := ss.Encrypter.Decrypt(p.EncryptedParams, ss.params(c, p))
+		if err != nil {
+			return fmt.Errorf("channel %s: %v", c.Name, err)
+		}
+		if err := json.Unmarshal(b, &p.Params); err != nil {
+			return err
+		}
+		p.EncryptedParams = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/microapis/messages-core/archive"
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/backup"
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/service"
+	"github.com/microapis/messages-core/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	channeldb "github.com/microapis/messages-core/channel/database"
+	"github.com/microapis/messages-core/channel/database/redis"
+	messagedb "github.com/microapis/messages-core/message/database"
+	"github.com/microapis/messages-core/message/database/bolt"
+	pb "github.com/microapis/messages-core/proto"
+	queueredis "github.com/microapis/messages-core/queue/redis"
+)
+
+const usage = `usage: messages-admin <command> [flags]
+
+The export, import, restore and rotate commands open the messages db directly, so
+the service must be stopped.
+
+commands:
+  export    export messages to archive files
+  import    restore messages from archive files
+  backup    download a snapshot from a running service
+  restore   rebuild the messages db, queue and channels from a snapshot
+  rotate    encrypt again the messages and channels with the current key
+  config    print the effective service config, accepts the service flags
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "import":
+		err = importArchive(os.Args[2:])
+	case "backup":
+		err = backupSnapshot(os.Args[2:])
+	case "restore":
+		err = restoreSnapshot(os.Args[2:])
+	case "rotate":
+		err = rotateKeys(os.Args[2:])
+	case "config":
+		err = printConfig(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func openStore(path, tenantID, keyfile string) (*bolt.MessageStore, error) {
+	dst, err := messagedb.NewBoltDatastore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ms, err := bolt.NewMessageStore(dst)
+	if err != nil {
+		return nil, err
+	}
+	if ms.Encrypter, err = openEncrypter(keyfile); err != nil {
+		return nil, err
+	}
+
+	return ms.For(tenantID)
+}
+
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	db := fs.String("db", "messages.db", "path of the messages db")
+	to := fs.String("to", "", "destination directory or s3://bucket/prefix url")
+	format := fs.String("format", archive.NDJSON, "format of the files, ndjson or parquet")
+	maxRecords := fs.Int("max-records", 0, "messages per file, 0 writes a single file")
+	channel := fs.String("channel", "", "export only the messages of this channel")
+	status := fs.String("status", "", "export only the messages with this status")
+	since := fs.Duration("since", 0, "export only the messages scheduled after now minus since")
+	until := fs.Duration("until", 0, "export only the messages scheduled before now minus until")
+	archived := fs.Bool("archived", true, "include the archived messages")
+	tenantID := fs.String("tenant", "", "export the messages of this tenant, empty for the default one")
+	keyfile := fs.String("keyfile", os.Getenv("MESSAGES_ENCRYPTION_KEYFILE"), "keyfile used to seal the content stored in plaintext")
+	fs.Parse(args)
+
+	if *to == "" {
+		return fmt.Errorf("export: -to is required")
+	}
+
+	ms, err := openStore(*db, *tenantID, *keyfile)
+	if err != nil {
+		return err
+	}
+	sink, err := archive.NewSink(*to)
+	if err != nil {
+		return err
+	}
+	if sink, err = sink.For(*tenantID); err != nil {
+		return err
+	}
+
+	f := archive.Filter{
+		Channel:  *channel,
+		Status:   *status,
+		Archived: *archived,
+	}
+	if *since > 0 {
+		f.Since = time.Now().Add(-*since)
+	}
+	if *until > 0 {
+		f.Until = time.Now().Add(-*until)
+	}
+
+	e := &archive.Exporter{
+		Store:      ms,
+		Sink:       sink,
+		Format:     *format,
+		MaxRecords: *maxRecords,
+	}
+	files, n, err := e.Export(f)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d messages to %v", n, files)
+	return nil
+}
+
+func importArchive(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	db := fs.String("db", "messages.db", "path of the messages db")
+	from := fs.String("from", "", "source directory or s3://bucket/prefix url")
+	tenantID := fs.String("tenant", "", "import the messages on this tenant, empty for the default one")
+	keyfile := fs.String("keyfile", os.Getenv("MESSAGES_ENCRYPTION_KEYFILE"), "keyfile used to seal the content exported in plaintext")
+	fs.Parse(args)
+
+	if *from == "" {
+		return fmt.Errorf("import: -from is required")
+	}
+
+	ms, err := openStore(*db, *tenantID, *keyfile)
+	if err != nil {
+		return err
+	}
+	sink, err := archive.NewSink(*from)
+	if err != nil {
+		return err
+	}
+
+	n, err := archive.Import(sink, ms)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d messages from %s", n, *from)
+	return nil
+}
+
+func backupSnapshot(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:5020", "address of the messages service")
+	out := fs.String("out", "", "path of the snapshot file, defaults to messages-<time>.tar.gz")
+	var tc tlsconfig.Config
+	fs.StringVar(&tc.CAFile, "tls-ca-file", "", "CA bundle used to verify the service, enables TLS")
+	fs.StringVar(&tc.CertFile, "tls-cert-file", "", "client certificate presented to the service")
+	fs.StringVar(&tc.KeyFile, "tls-key-file", "", "private key of the client certificate")
+	apiKey := fs.String("api-key", os.Getenv("MESSAGES_API_KEY"), "api key of an admin client")
+	fs.Parse(args)
+
+	if *out == "" {
+		*out = fmt.Sprintf("messages-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	opt := grpc.WithInsecure()
+	if tc.Enabled() {
+		if err := tc.Validate(); err != nil {
+			return err
+		}
+		creds, err := tc.ClientCredentials("")
+		if err != nil {
+			return err
+		}
+		defer creds.Close()
+		opt = grpc.WithTransportCredentials(creds)
+	}
+
+	conn, err := grpc.Dial(*addr, opt)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if *apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, auth.APIKeyHeader, *apiKey)
+	}
+
+	stream, err := pb.NewSchedulerServiceClient(conn).Backup(ctx, &pb.BackupRequest{})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(*out)
+			return err
+		}
+
+		if _, err := f.Write(chunk.GetData()); err != nil {
+			f.Close()
+			os.Remove(*out)
+			return err
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	log.Printf("Backup written to %s", *out)
+	return nil
+}
+
+func restoreSnapshot(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	db := fs.String("db", "messages.db", "path of the messages db")
+	redisURL := fs.String("redis", "", "redis url of the queue and channels, empty skips both")
+	from := fs.String("from", "", "path of the snapshot file")
+	fs.Parse(args)
+
+	if *from == "" {
+		return fmt.Errorf("restore: -from is required")
+	}
+
+	f, err := os.Open(*from)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var pq queue.Queue
+	var cs *redis.ChannelStore
+	if *redisURL != "" {
+		if pq, err = queueredis.NewQueue(*redisURL, queueredis.PoolConfig{}); err != nil {
+			return err
+		}
+
+		dst, err := channeldb.NewRedisDatastore(*redisURL, 0)
+		if err != nil {
+			return err
+		}
+		if cs, err = redis.NewChannelStore(dst); err != nil {
+			return err
+		}
+	}
+
+	if err := backup.Restore(f, *db, pq, cs); err != nil {
+		return err
+	}
+
+	log.Printf("Restored %s from %s", *db, *from)
+	return nil
+}
+
+func rotateKeys(args []string) error {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	db := fs.String("db", "messages.db", "path of the messages db")
+	redisURL := fs.String("redis", "", "redis url of the channels, empty skips them")
+	keyfile := fs.String("keyfile", os.Getenv("MESSAGES_ENCRYPTION_KEYFILE"), "keyfile of the encryption at rest")
+	newKey := fs.String("new-key", "", "add a new key with this id to the keyfile and make it the current one")
+	fs.Parse(args)
+
+	if *keyfile == "" {
+		return fmt.Errorf("rotate: -keyfile is required")
+	}
+	if *newKey != "" {
+		if err := encryption.AddKey(*keyfile, *newKey); err != nil {
+			return err
+		}
+		log.Printf("Added key %s to %s", *newKey, *keyfile)
+	}
+
+	ms, err := openStore(*db, "", *keyfile)
+	if err != nil {
+		return err
+	}
+	tenants, err := ms.Dst.Tenants()
+	if err != nil {
+		return err
+	}
+	tenants = append([]string{""}, tenants...)
+
+	var cs *redis.ChannelStore
+	if *redisURL != "" {
+		dst, err := channeldb.NewRedisDatastore(*redisURL, 0)
+		if err != nil {
+			return err
+		}
+		if cs, err = redis.NewChannelStore(dst); err != nil {
+			return err
+		}
+		cs.Encrypter = ms.Encrypter
+	}
+
+	for _, t := range tenants {
+		tms, err := ms.For(t)
+		if err != nil {
+			return err
+		}
+		n, err := tms.Rotate()
+		if err != nil {
+			return err
+		}
+		log.Printf("Encrypted again %d messages of tenant %q", n, t)
+
+		if cs == nil {
+			continue
+		}
+		if n, err = cs.For(t).Rotate(); err != nil {
+			return err
+		}
+		log.Printf("Encrypted again %d channels of tenant %q", n, t)
+	}
+
+	return nil
+}
+
+// openEncrypter returns the encrypter of keyfile, nil if it is empty.
+func openEncrypter(keyfile string) (*encryption.Encrypter, error) {
+	if keyfile == "" {
+		return nil, nil
+	}
+
+	keys, err := encryption.NewLocalKeyProvider(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.New(keys), nil
+}
+
+func printConfig(args []string) error {
+	c, err := service.LoadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	return c.Print(os.Stdout)
+}
@@ -0,0 +1,194 @@
+// Package content validates the typed content of the messages against the
+// schema of their channel.
+package content
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/microapis/messages-core/channel"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Content types ...
+const (
+	// Text is an opaque content, the default one.
+	Text = "text/plain"
+	// JSON is a JSON document.
+	JSON = "application/json"
+	// Protobuf is a google.protobuf.Any, stored base64 encoded.
+	Protobuf = "application/protobuf"
+)
+
+// InvalidError describes why a content does not match the schema.
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return "invalid content: " + e.Reason
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &InvalidError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Encode returns the content to store and its type from the text content
+// and the Any content of a request, only one of them can be set. The Any
+// content is marshaled and base64 encoded, so it is kept as a string.
+func Encode(contentType, text string, a *anypb.Any) (string, string, error) {
+	if a != nil {
+		if text != "" {
+			return "", "", errors.New("content and any_content are exclusive")
+		}
+		if contentType != "" && contentType != Protobuf {
+			return "", "", fmt.Errorf("content type of any_content must be %s", Protobuf)
+		}
+		b, err := proto.Marshal(a)
+		if err != nil {
+			return "", "", err
+		}
+		return base64.StdEncoding.EncodeToString(b), Protobuf, nil
+	}
+
+	if contentType == Protobuf {
+		return "", "", fmt.Errorf("content of type %s must be sent on any_content", Protobuf)
+	}
+	if err := Check(contentType, text); err != nil {
+		return "", "", err
+	}
+
+	return text, contentType, nil
+}
+
+// Check fails if the stored content is not of the given type.
+func Check(contentType, content string) error {
+	switch contentType {
+	case "", Text:
+		return nil
+	case JSON:
+		if !json.Valid([]byte(content)) {
+			return errors.New("content is not valid JSON")
+		}
+		return nil
+	case Protobuf:
+		_, err := Any(content)
+		return err
+	}
+
+	return fmt.Errorf("unsupported content type %q", contentType)
+}
+
+// Any decodes a content of type Protobuf.
+func Any(content string) (*anypb.Any, error) {
+	b, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, err
+	}
+
+	a := new(anypb.Any)
+	if err := proto.Unmarshal(b, a); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// schemaURL identifies the JSON Schema of a channel, so the errors do not
+// refer to a local file.
+const schemaURL = "mem://channel/schema.json"
+
+// Validator validates the content against the schema of a channel.
+type Validator struct {
+	json    *jsonschema.Schema
+	message protoreflect.MessageDescriptor
+}
+
+// Compile returns the validator of the schema s, failing if it is not
+// a valid JSON Schema or the descriptor does not have its message.
+func Compile(s *channel.Schema) (*Validator, error) {
+	switch {
+	case len(s.JSON) > 0 && len(s.Descriptor) > 0:
+		return nil, errors.New("schema must have either json or descriptor")
+	case len(s.JSON) > 0:
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource(schemaURL, bytes.NewReader(s.JSON)); err != nil {
+			return nil, err
+		}
+		js, err := c.Compile(schemaURL)
+		if err != nil {
+			return nil, err
+		}
+		return &Validator{json: js}, nil
+	case len(s.Descriptor) > 0:
+		var fds descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(s.Descriptor, &fds); err != nil {
+			return nil, err
+		}
+		files, err := protodesc.NewFiles(&fds)
+		if err != nil {
+			return nil, err
+		}
+		d, err := files.FindDescriptorByName(protoreflect.FullName(s.Message))
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %v", s.Message, err)
+		}
+		md, ok := d.(protoreflect.MessageDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a message", s.Message)
+		}
+		return &Validator{message: md}, nil
+	}
+
+	return nil, errors.New("schema must have json or descriptor")
+}
+
+// Validate fails with an *InvalidError if content, of the given type,
+// does not match the schema.
+func (v *Validator) Validate(contentType, content string) error {
+	if v.json != nil {
+		if contentType != JSON {
+			return invalid("content type must be %s", JSON)
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal([]byte(content), &doc); err != nil {
+			return invalid("%v", err)
+		}
+		if err := v.json.Validate(doc); err != nil {
+			return invalid("%v", err)
+		}
+		return nil
+	}
+
+	if contentType != Protobuf {
+		return invalid("content type must be %s", Protobuf)
+	}
+
+	a, err := Any(content)
+	if err != nil {
+		return invalid("%v", err)
+	}
+	if name := a.MessageName(); name != v.message.FullName() {
+		return invalid("message must be %s, not %s", v.message.FullName(), name)
+	}
+
+	m := dynamicpb.NewMessage(v.message)
+	if err := proto.Unmarshal(a.GetValue(), m); err != nil {
+		return invalid("%v", err)
+	}
+	if len(m.GetUnknown()) > 0 {
+		return invalid("unknown fields for %s", v.message.FullName())
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/microapis/messages-core/channel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func encode(t *testing.T, m proto.Message) string {
+	t.Helper()
+	a, err := anypb.New(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _, err := Encode("", "", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func descriptor(t *testing.T) []byte {
+	t.Helper()
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(wrapperspb.File_google_protobuf_wrappers_proto),
+	}}
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema channel.Schema
+		ok     bool
+	}{
+		{"json", channel.Schema{JSON: json.RawMessage(`{"type":"object"}`)}, true},
+		{"descriptor", channel.Schema{Descriptor: descriptor(t), Message: "google.protobuf.StringValue"}, true},
+		{"invalid json", channel.Schema{JSON: json.RawMessage(`{"type":`)}, false},
+		{"invalid json schema", channel.Schema{JSON: json.RawMessage(`{"type":"made-up"}`)}, false},
+		{"unknown message", channel.Schema{Descriptor: descriptor(t), Message: "google.protobuf.Nothing"}, false},
+		{"json and descriptor", channel.Schema{JSON: json.RawMessage(`{}`), Descriptor: descriptor(t)}, false},
+		{"empty", channel.Schema{}, false},
+	}
+
+	for _, tt := range tests {
+		if _, err := Compile(&tt.schema); (err == nil) != tt.ok {
+			t.Errorf("%s: Compile() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	js, err := Compile(&channel.Schema{JSON: json.RawMessage(`{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := Compile(&channel.Schema{Descriptor: descriptor(t), Message: "google.protobuf.StringValue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		v           *Validator
+		contentType string
+		content     string
+		ok          bool
+	}{
+		{"json", js, JSON, `{"name":"Ana"}`, true},
+		{"json missing property", js, JSON, `{}`, false},
+		{"json of another type", js, JSON, `{"name":1}`, false},
+		{"json as text", js, Text, `{"name":"Ana"}`, false},
+		{"protobuf", pb, Protobuf, encode(t, wrapperspb.String("Hi")), true},
+		{"protobuf of another message", pb, Protobuf, encode(t, durationpb.New(0)), false},
+		{"protobuf not encoded", pb, Protobuf, "Hi", false},
+		{"protobuf as json", pb, JSON, `{}`, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.v.Validate(tt.contentType, tt.content)
+		var invalid *InvalidError
+		switch {
+		case tt.ok && err != nil:
+			t.Errorf("%s: Validate() = %v, want nil", tt.name, err)
+		case !tt.ok && !errors.As(err, &invalid):
+			t.Errorf("%s: Validate() = %v, want an *InvalidError", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeyProvider keeps the key encryption keys, which wrap the data keys
+// used to encrypt each record. It may be backed by a local keyfile or by
+// a KMS, where the keys never leave the service.
+type KeyProvider interface {
+	// Current returns the id of the key used to wrap new data keys.
+	Current() string
+
+	// Wrap encrypts the data key dek with the key keyID.
+	Wrap(keyID string, dek []byte) ([]byte, error)
+
+	// Unwrap decrypts a data key wrapped with the key keyID.
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Envelope is an encrypted record, with the data key wrapped by the key
+// encryption key KeyID. The nonce of the envelopes bound to their record
+// is prefixed with the bound version, the former ones have no prefix.
+type Envelope struct {
+	KeyID string `json:"key_id"`
+	Key   []byte `json:"key"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// bound prefixes the nonce of the envelopes sealed with additional data.
+const bound = 1
+
+// nonceSize is the standard nonce size of GCM.
+const nonceSize = 12
+
+// Encrypter encrypts the records with AES-256-GCM and a new data key per
+// record.
+type Encrypter struct {
+	Provider KeyProvider
+}
+
+// New ...
+func New(p KeyProvider) *Encrypter {
+	return &Encrypter{
+		Provider: p,
+	}
+}
+
+// Encrypt returns the envelope of plaintext, wrapped with the current key.
+// The envelope is bound to ad, as the id of the record, which is
+// authenticated but not encrypted, so it cannot be decrypted as the
+// envelope of another record.
+func (e *Encrypter) Encrypt(plaintext, ad []byte) (*Envelope, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, err
+	}
+
+	nonce, data, err := seal(dek, plaintext, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := e.Provider.Current()
+	wrapped, err := e.Provider.Wrap(keyID, dek)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		KeyID: keyID,
+		Key:   wrapped,
+		Nonce: append([]byte{bound}, nonce...),
+		Data:  data,
+	}, nil
+}
+
+// Decrypt returns the plaintext of env, which fails unless ad is the one
+// given to Encrypt. The envelopes encrypted before they were bound to
+// their record ignore ad.
+func (e *Encrypter) Decrypt(env *Envelope, ad []byte) ([]byte, error) {
+	dek, err := e.Provider.Unwrap(env.KeyID, env.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, ok := env.nonce()
+	if !ok {
+		ad = nil
+	}
+
+	return open(dek, nonce, env.Data, ad)
+}
+
+// nonce returns the nonce of env without its prefix, reporting whether
+// env is bound to its record.
+func (env *Envelope) nonce() ([]byte, bool) {
+	if len(env.Nonce) == 1+nonceSize && env.Nonce[0] == bound {
+		return env.Nonce[1:], true
+	}
+
+	return env.Nonce, false
+}
+
+// Stale reports whether env was not encrypted with the current key, or
+// is not bound to its record, so it must be encrypted again on a key
+// rotation.
+func (e *Encrypter) Stale(env *Envelope) bool {
+	_, ok := env.nonce()
+	return env.KeyID != e.Provider.Current() || !ok
+}
+
+func seal(key, plaintext, ad []byte) ([]byte, []byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return nonce, aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func open(key, nonce, data, ad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+
+	plaintext, err := aead.Open(nil, nonce, data, ad)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt: %v", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
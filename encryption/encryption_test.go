@@ -0,0 +1,119 @@
+package encryption
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func newProvider(t *testing.T, path string, keyIDs ...string) *LocalKeyProvider {
+	t.Helper()
+	for _, id := range keyIDs {
+		if err := AddKey(path, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := NewLocalKeyProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestEncrypt(t *testing.T) {
+	e := New(newProvider(t, filepath.Join(t.TempDir(), "keys.json"), "k1"))
+	plaintext := []byte("content")
+
+	env, err := e.Encrypt(plaintext, []byte("id1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(env.Data, plaintext) {
+		t.Error("Encrypt() kept the plaintext")
+	}
+	if e.Stale(env) {
+		t.Error("Stale() of a new envelope = true, want false")
+	}
+
+	tampered := func(f func(env *Envelope)) *Envelope {
+		c := *env
+		c.Data = append([]byte(nil), env.Data...)
+		f(&c)
+		return &c
+	}
+
+	tests := []struct {
+		name string
+		env  *Envelope
+		ad   string
+		ok   bool
+	}{
+		{"round trip", env, "id1", true},
+		// a ciphertext copied to another record is not decrypted.
+		{"another record", env, "id2", false},
+		{"without additional data", env, "", false},
+		{"modified data", tampered(func(env *Envelope) { env.Data[0] ^= 1 }), "id1", false},
+		{"nonce without prefix", tampered(func(env *Envelope) { env.Nonce = env.Nonce[1:] }), "id1", false},
+		{"unknown key", tampered(func(env *Envelope) { env.KeyID = "k2" }), "id1", false},
+	}
+
+	for _, tt := range tests {
+		got, err := e.Decrypt(tt.env, []byte(tt.ad))
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Decrypt() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+		if tt.ok && !bytes.Equal(got, plaintext) {
+			t.Errorf("%s: Decrypt() = %q, want %q", tt.name, got, plaintext)
+		}
+	}
+}
+
+func TestDecryptUnbound(t *testing.T) {
+	p := newProvider(t, filepath.Join(t.TempDir(), "keys.json"), "k1")
+	e := New(p)
+
+	// an envelope sealed before the envelopes were bound to their record.
+	dek := make([]byte, 32)
+	nonce, data, err := seal(dek, []byte("content"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := p.Wrap("k1", dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Envelope{KeyID: "k1", Key: wrapped, Nonce: nonce, Data: data}
+
+	if got, err := e.Decrypt(env, []byte("id1")); err != nil || string(got) != "content" {
+		t.Errorf("Decrypt() = %q, %v, want %q", got, err, "content")
+	}
+	if !e.Stale(env) {
+		t.Error("Stale() of an unbound envelope = false, want true")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	old := New(newProvider(t, path, "k1"))
+	env, err := old.Encrypt([]byte("content"), []byte("id1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the envelopes of the former key are stale, and still decrypted.
+	e := New(newProvider(t, path, "k2"))
+	if !e.Stale(env) {
+		t.Error("Stale() of an envelope of the former key = false, want true")
+	}
+	if got, err := e.Decrypt(env, []byte("id1")); err != nil || string(got) != "content" {
+		t.Errorf("Decrypt() with the former key = %q, %v, want %q", got, err, "content")
+	}
+
+	rotated, err := e.Encrypt([]byte("content"), []byte("id1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.KeyID != "k2" || e.Stale(rotated) {
+		t.Errorf("Encrypt() after the rotation = key %q, stale %v, want key k2", rotated.KeyID, e.Stale(rotated))
+	}
+}
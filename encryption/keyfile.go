@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+var _ KeyProvider = (*LocalKeyProvider)(nil)
+
+// Keyfile is the JSON file of a LocalKeyProvider, with base64 encoded
+// 32 bytes keys by id:
+//
+//	{"current": "2024-06", "keys": {"2024-01": "...", "2024-06": "..."}}
+type Keyfile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider wraps the data keys with the keys of a Keyfile.
+type LocalKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewLocalKeyProvider loads the keyfile on path.
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kf Keyfile
+	if err := json.Unmarshal(b, &kf); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	p := &LocalKeyProvider{
+		current: kf.Current,
+		keys:    make(map[string][]byte),
+	}
+	for id, k := range kf.Keys {
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %v", path, id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%s: key %q must have 32 bytes", path, id)
+		}
+		p.keys[id] = key
+	}
+	if _, ok := p.keys[p.current]; !ok {
+		return nil, fmt.Errorf("%s: current key %q not found", path, p.current)
+	}
+
+	return p, nil
+}
+
+// Current ...
+func (p *LocalKeyProvider) Current() string {
+	return p.current
+}
+
+// Wrap ...
+func (p *LocalKeyProvider) Wrap(keyID string, dek []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+
+	nonce, data, err := seal(key, dek, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nonce, data...), nil
+}
+
+// Unwrap ...
+func (p *LocalKeyProvider) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid wrapped key")
+	}
+
+	return open(key, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], nil)
+}
+
+// AddKey adds a new random key with the given id to the keyfile on path,
+// creating it if it does not exist, and makes it the current one.
+func AddKey(path, keyID string) error {
+	kf := Keyfile{
+		Keys: make(map[string]string),
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &kf); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	if _, ok := kf.Keys[keyID]; ok {
+		return fmt.Errorf("%s: key %q already exists", path, keyID)
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return err
+	}
+	kf.Keys[keyID] = base64.StdEncoding.EncodeToString(key)
+	kf.Current = keyID
+
+	b, err = json.MarshalIndent(&kf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0600)
+}
@@ -0,0 +1,237 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Services reported by the health server, besides a service per
+// component and per channel backend. The empty service is Readiness.
+const (
+	// Liveness is not serving when a liveness component, as the run loop
+	// of the scheduler, is failing, and the process should be restarted.
+	Liveness = "liveness"
+	// Readiness is not serving when any component is failing, and the
+	// service should not receive requests.
+	Readiness = "readiness"
+	// ChannelPrefix prefixes the service of the backend of each channel.
+	// The backends do not affect the readiness, as a single failing
+	// backend must not stop the other channels.
+	ChannelPrefix = "channel/"
+)
+
+// methodPrefix is the prefix of the methods of the health service.
+const methodPrefix = "/grpc.health.v1.Health/"
+
+// IsHealthMethod reports whether the full method belongs to the health
+// service, which is exempt from authentication and rate limits so the
+// probes always reach it.
+func IsHealthMethod(method string) bool {
+	return strings.HasPrefix(method, methodPrefix)
+}
+
+// Degraded wraps the error of a component that is failing but does not
+// stop the service, as a queue buffering the messages meanwhile. The
+// component is reported as not serving without affecting the readiness.
+func Degraded(err error) error {
+	return &degraded{err}
+}
+
+type degraded struct {
+	err error
+}
+
+func (e *degraded) Error() string { return "degraded: " + e.err.Error() }
+
+func (e *degraded) Unwrap() error { return e.err }
+
+// IsDegraded reports whether err was returned by Degraded.
+func IsDegraded(err error) bool {
+	var d *degraded
+	return errors.As(err, &d)
+}
+
+// Check reports the health of a component, nil when it is healthy.
+type Check func(ctx context.Context) error
+
+// Checks reports the health of a group of components by name, as the
+// backends of the channels.
+type Checks func(ctx context.Context) map[string]error
+
+type component struct {
+	name     string
+	check    Check
+	liveness bool
+}
+
+// Monitor runs the checks periodically and reports their status on a
+// grpc.health.v1 server.
+type Monitor struct {
+	// Server is the health server to register on the gRPC server.
+	Server *health.Server
+
+	// Interval between two rounds of checks, 10s by default.
+	Interval time.Duration
+
+	// Timeout of each check, the Interval by default.
+	Timeout time.Duration
+
+	mu         sync.Mutex
+	components []component
+	channels   Checks
+	statuses   map[string]healthpb.HealthCheckResponse_ServingStatus
+}
+
+// NewMonitor returns a monitor with every service not serving until the
+// first checks run.
+func NewMonitor(interval time.Duration) *Monitor {
+	m := &Monitor{
+		Server:   health.NewServer(),
+		Interval: interval,
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+	}
+	for _, s := range []string{"", Liveness, Readiness} {
+		m.Server.SetServingStatus(s, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return m
+}
+
+// Add adds the component name, which affects the readiness and, when
+// liveness is set, the liveness.
+func (m *Monitor) Add(name string, check Check, liveness bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.components = append(m.components, component{name, check, liveness})
+}
+
+// AddChannels sets the checks of the backends of the channels, reported
+// as ChannelPrefix + name.
+func (m *Monitor) AddChannels(checks Checks) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.channels = checks
+}
+
+// Register registers the health server on s.
+func (m *Monitor) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, m.Server)
+}
+
+// Run runs the checks until ctx is done.
+func (m *Monitor) Run(ctx context.Context) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.CheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			m.Server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll runs every check once and updates the statuses.
+func (m *Monitor) CheckAll(ctx context.Context) {
+	m.mu.Lock()
+	components := m.components
+	channels := m.channels
+	m.mu.Unlock()
+
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = m.Interval
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	live, ready := true, true
+	for _, c := range components {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		err := c.check(cctx)
+		cancel()
+
+		m.set(c.name, err)
+		if err != nil && !IsDegraded(err) {
+			ready = false
+			if c.liveness {
+				live = false
+			}
+		}
+	}
+
+	if channels != nil {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		errs := channels(cctx)
+		cancel()
+
+		names := make([]string, 0, len(errs))
+		for name := range errs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m.set(ChannelPrefix+name, errs[name])
+		}
+	}
+
+	m.set(Liveness, boolErr(live))
+	m.set(Readiness, boolErr(ready))
+}
+
+// set updates the status of service, logging its changes.
+func (m *Monitor) set(service string, err error) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	m.mu.Lock()
+	prev, ok := m.statuses[service]
+	m.statuses[service] = status
+	m.mu.Unlock()
+
+	if ok && prev != status || !ok && err != nil {
+		if err != nil {
+			slog.Warn("health check failing", "service", service, "error", err)
+		} else {
+			slog.Info("health check recovered", "service", service)
+		}
+	}
+
+	m.Server.SetServingStatus(service, status)
+	if service == Readiness {
+		m.Server.SetServingStatus("", status)
+	}
+}
+
+var errUnhealthy = errors.New("unhealthy components")
+
+func boolErr(ok bool) error {
+	if ok {
+		return nil
+	}
+
+	return errUnhealthy
+}
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	serving    = healthpb.HealthCheckResponse_SERVING
+	notServing = healthpb.HealthCheckResponse_NOT_SERVING
+)
+
+func statusOf(t *testing.T, m *Monitor, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := m.Server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) = %v", service, err)
+	}
+	return resp.Status
+}
+
+func TestIsHealthMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"/grpc.health.v1.Health/Check", true},
+		{"/grpc.health.v1.Health/Watch", true},
+		{"/proto.SchedulerService/Put", false},
+		{"/grpc.health.v1.HealthX/Check", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsHealthMethod(tt.method); got != tt.want {
+			t.Errorf("IsHealthMethod(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestDegraded(t *testing.T) {
+	err := errors.New("redis down")
+	d := fmt.Errorf("queue: %w", Degraded(err))
+
+	if !IsDegraded(d) {
+		t.Errorf("IsDegraded(%v) = false, want true", d)
+	}
+	if !errors.Is(d, err) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", d, err)
+	}
+	if IsDegraded(err) {
+		t.Errorf("IsDegraded(%v) = true, want false", err)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	m := NewMonitor(time.Second)
+	for _, s := range []string{"", Liveness, Readiness} {
+		if got := statusOf(t, m, s); got != notServing {
+			t.Errorf("before checks: %q = %v, want %v", s, got, notServing)
+		}
+	}
+
+	var run, queue, store error
+	channels := map[string]error{}
+	m.Add("run", func(context.Context) error { return run }, true)
+	m.Add("queue", func(context.Context) error { return queue }, false)
+	m.Add("store", func(context.Context) error { return store }, false)
+	m.AddChannels(func(context.Context) map[string]error { return channels })
+
+	tests := []struct {
+		name     string
+		setup    func()
+		statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			"healthy",
+			func() { channels["email"] = nil },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, Liveness: serving, Readiness: serving,
+				"run": serving, "queue": serving, "store": serving, ChannelPrefix + "email": serving,
+			},
+		},
+		{
+			"failing channel",
+			func() { channels["email"] = errors.New("timeout") },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, Liveness: serving, Readiness: serving, ChannelPrefix + "email": notServing,
+			},
+		},
+		{
+			"degraded queue",
+			func() { channels["email"] = nil; queue = Degraded(errors.New("buffering")) },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, Liveness: serving, Readiness: serving, "queue": notServing,
+			},
+		},
+		{
+			"failing store",
+			func() { queue = nil; store = errors.New("closed") },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": notServing, Liveness: serving, Readiness: notServing, "store": notServing,
+			},
+		},
+		{
+			"failing run loop",
+			func() { store = nil; run = errors.New("stopped") },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": notServing, Liveness: notServing, Readiness: notServing, "run": notServing,
+			},
+		},
+		{
+			"recovered",
+			func() { run = nil },
+			map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"": serving, Liveness: serving, Readiness: serving, "run": serving,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.setup()
+		m.CheckAll(context.Background())
+
+		for s, want := range tt.statuses {
+			if got := statusOf(t, m, s); got != want {
+				t.Errorf("%s: %q = %v, want %v", tt.name, s, got, want)
+			}
+		}
+	}
+}
+
+func TestCheckAllTimeout(t *testing.T) {
+	m := NewMonitor(time.Second)
+	m.Timeout = 10 * time.Millisecond
+	m.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, false)
+
+	m.CheckAll(context.Background())
+
+	if got := statusOf(t, m, "slow"); got != notServing {
+		t.Errorf("slow = %v, want %v", got, notServing)
+	}
+	if got := statusOf(t, m, Readiness); got != notServing {
+		t.Errorf("%q = %v, want %v", Readiness, got, notServing)
+	}
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/microapis/messages-core/secret"
+)
+
+// Formats ...
+const (
+	// Text ...
+	Text = "text"
+	// JSON ...
+	JSON = "json"
+)
+
+// Redaction modes ...
+const (
+	// Mask replaces the value by its size.
+	Mask = "mask"
+	// Hash replaces the value by a short HMAC keyed with HashKey, so equal
+	// values can be correlated without exposing them, and the short values
+	// cannot be guessed by hashing the candidates.
+	Hash = "hash"
+	// Secrets redacts only the params named like a secret, see
+	// secret.Redact.
+	Secrets = "secrets"
+	// None logs the value as it is.
+	None = "none"
+)
+
+// Keys of the attributes redacted by the policy.
+const (
+	ContentKey = "content"
+	ParamsKey  = "params"
+)
+
+// Redaction is the policy applied over the content of the messages and
+// the params of the providers on every log record.
+type Redaction struct {
+	// Content is Mask (default), Hash or None.
+	Content string `yaml:"content" toml:"content" json:"content"`
+	// Params is Secrets (default), Mask or None.
+	Params string `yaml:"params" toml:"params" json:"params"`
+	// HashKey keys the HMAC of the Hash mode, which requires it.
+	HashKey string `yaml:"hash_key" toml:"hash_key" json:"hash_key"`
+}
+
+// Validate ...
+func (r Redaction) Validate() error {
+	switch r.Content {
+	case "", Mask, Hash, None:
+	default:
+		return fmt.Errorf("content %q must be mask, hash or none", r.Content)
+	}
+	if r.Content == Hash && r.HashKey == "" {
+		return fmt.Errorf("hash_key is required by the hash content")
+	}
+
+	switch r.Params {
+	case "", Secrets, Mask, None:
+	default:
+		return fmt.Errorf("params %q must be secrets, mask or none", r.Params)
+	}
+
+	return nil
+}
+
+// Config ...
+type Config struct {
+	// Level is debug, info (default), warn or error.
+	Level string
+	// Format is Text (default) or JSON.
+	Format    string
+	Redaction Redaction
+}
+
+// New returns a logger writing to w.
+func New(w io.Writer, c Config) (*slog.Logger, error) {
+	var level slog.Level
+	if c.Level != "" {
+		if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Redaction.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: c.Redaction.replace,
+	}
+
+	switch c.Format {
+	case "", Text:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case JSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+
+	return nil, fmt.Errorf("unknown log format %q, must be text or json", c.Format)
+}
+
+// Setup makes the logger of c the default one, which is also used by the
+// log package.
+func Setup(c Config) error {
+	l, err := New(os.Stderr, c)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(l)
+
+	return nil
+}
+
+// replace applies the policy over the content and params attributes. The
+// errors are logged by their message, as the text handler would print the
+// stack of the ones created by github.com/pkg/errors.
+func (r Redaction) replace(_ []string, a slog.Attr) slog.Attr {
+	if err, ok := a.Value.Any().(error); ok {
+		return slog.String(a.Key, err.Error())
+	}
+
+	switch strings.ToLower(a.Key) {
+	case ContentKey:
+		return slog.String(a.Key, r.redactContent(a.Value.String()))
+	case ParamsKey:
+		params, ok := a.Value.Any().(map[string]string)
+		if !ok {
+			return a
+		}
+		return slog.Any(a.Key, redactParams(r.Params, params))
+	}
+
+	return a
+}
+
+func (r Redaction) redactContent(content string) string {
+	switch r.Content {
+	case None:
+		return content
+	case Hash:
+		mac := hmac.New(sha256.New, []byte(r.HashKey))
+		mac.Write([]byte(content))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	}
+
+	return fmt.Sprintf("[%d bytes]", len(content))
+}
+
+func redactParams(mode string, params map[string]string) map[string]string {
+	switch mode {
+	case None:
+		return params
+	case Mask:
+		masked := make(map[string]string, len(params))
+		for k := range params {
+			masked[k] = secret.Redacted
+		}
+		return masked
+	}
+
+	return secret.Redact(params)
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/microapis/messages-core/secret"
+)
+
+// record logs the content and params with r and returns the JSON record.
+func record(t *testing.T, r Redaction, content string, params map[string]string) map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	l, err := New(&buf, Config{Format: JSON, Redaction: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("message", ContentKey, content, ParamsKey, params)
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestRedactContent(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Redaction
+		want string
+	}{
+		{"default", Redaction{}, "[4 bytes]"},
+		{"mask", Redaction{Content: Mask}, "[4 bytes]"},
+		{"none", Redaction{Content: None}, "1234"},
+	}
+
+	for _, tt := range tests {
+		if got := record(t, tt.r, "1234", nil)[ContentKey]; got != tt.want {
+			t.Errorf("%s: content = %v, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// the hashes of a content are equal under the same key only.
+	hash := func(key, content string) string {
+		t.Helper()
+		got, _ := record(t, Redaction{Content: Hash, HashKey: key}, content, nil)[ContentKey].(string)
+		if !strings.HasPrefix(got, "hmac:") || strings.Contains(got, content) {
+			t.Errorf("hash of %q = %q, want an hmac", content, got)
+		}
+		return got
+	}
+	if hash("k1", "1234") != hash("k1", "1234") {
+		t.Error("hashes of the same content with the same key differ")
+	}
+	if hash("k1", "1234") == hash("k2", "1234") {
+		t.Error("hashes of the same content with different keys are equal")
+	}
+	if hash("k1", "1234") == hash("k1", "1235") {
+		t.Error("hashes of different contents are equal")
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	params := map[string]string{"api_key": "s3cr3t", "from": "ops@example.com"}
+
+	tests := []struct {
+		mode string
+		want map[string]interface{}
+	}{
+		{Secrets, map[string]interface{}{"api_key": secret.Redacted, "from": "ops@example.com"}},
+		{Mask, map[string]interface{}{"api_key": secret.Redacted, "from": secret.Redacted}},
+		{None, map[string]interface{}{"api_key": "s3cr3t", "from": "ops@example.com"}},
+	}
+
+	for _, tt := range tests {
+		got, _ := record(t, Redaction{Params: tt.mode}, "", params)[ParamsKey].(map[string]interface{})
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s: params[%s] = %v, want %v", tt.mode, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestRedactionValidate(t *testing.T) {
+	tests := []struct {
+		r  Redaction
+		ok bool
+	}{
+		{Redaction{}, true},
+		{Redaction{Content: Hash, HashKey: "key"}, true},
+		{Redaction{Content: Hash}, false},
+		{Redaction{Content: "sha256"}, false},
+		{Redaction{Params: Hash}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.r.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok %v", tt.r, err, tt.ok)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package bolt
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
+	"github.com/microapis/messages-core/encryption"
 	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/store"
 	"github.com/oklog/ulid"
 
 	db "github.com/microapis/messages-core/message/database"
@@ -13,6 +21,14 @@ import (
 // MessageStore ...
 type MessageStore struct {
 	Dst *db.BoltDatastore
+
+	// Tenant owns the messages of the store, the default tenant keeps them
+	// on the root buckets.
+	Tenant string
+
+	// Encrypter encrypts the content of the messages at rest, nil stores
+	// them in plaintext.
+	Encrypter *encryption.Encrypter
 }
 
 // NewMessageStore ...
@@ -22,26 +38,72 @@ func NewMessageStore(dst *db.BoltDatastore) (*MessageStore, error) {
 	}, nil
 }
 
-// AddMessage ...
-func (ss *MessageStore) AddMessage(m message.Message) error {
+// For returns a store of the messages of tenant, creating its buckets if
+// they do not exist.
+func (ss *MessageStore) For(tenantID string) (*MessageStore, error) {
+	err := ss.Dst.CreateBuckets(tenantID, db.MsgBucket, db.HistoryBucket, db.ArchiveBucket, db.VersionsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageStore{
+		Dst:       ss.Dst,
+		Tenant:    tenantID,
+		Encrypter: ss.Encrypter,
+	}, nil
+}
+
+// AddMessage stores m as received, failing with store.ErrConflict if a
+// message with its id exists.
+func (ss *MessageStore) AddMessage(m message.Message, source string) error {
 	err := ss.Dst.DB.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(db.MsgBucket)
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
 
 		k, merr := m.ID.MarshalBinary()
 		if merr != nil {
 			return merr
 		}
-		v, jerr := proto.Marshal(&pb.Message{
+		if b.Get(k) != nil {
+			return fmt.Errorf("message %s: %w", m.ID, store.ErrConflict)
+		}
+		msg := &pb.Message{
 			Id:       m.ID.String(),
 			Channel:  string(m.Channel),
 			Provider: string(m.Provider),
 			Content:  string(m.Content),
-			Status:   string(message.Pending),
-		})
+			Status:   string(message.Received),
+			Client:   m.Client,
+			Tenant:   m.Tenant,
+			Revision: 1,
+
+			ContentType:  m.ContentType,
+			TraceContext: m.TraceContext,
+
+			Template:        m.Template,
+			TemplateVersion: m.TemplateVersion,
+			Locale:          m.Locale,
+			Render:          m.Render,
+		}
+		if err := ss.seal(msg); err != nil {
+			return err
+		}
+		v, jerr := proto.Marshal(msg)
 		if jerr != nil {
 			return jerr
 		}
-		return b.Put(k, v)
+		if err := b.Put(k, v); err != nil {
+			return err
+		}
+
+		t := message.Transition{
+			To:       message.Received,
+			Source:   source,
+			Provider: m.Provider,
+		}
+		if source == message.SourceAPI {
+			t.Client = m.Client
+		}
+		return addTransition(tx, ss.Tenant, k, t)
 	})
 	if err != nil {
 		return err
@@ -50,20 +112,24 @@ func (ss *MessageStore) AddMessage(m message.Message) error {
 	return nil
 }
 
-// Get ...
+// Get returns the message with the given id, failing with
+// store.ErrNotFound if it does not exist or was archived.
 func (ss *MessageStore) Get(id ulid.ULID) (*message.Message, error) {
 	var msg pb.Message
 	err := ss.Dst.DB.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(db.MsgBucket)
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
 		k, err := id.MarshalBinary()
 		if err != nil {
 			return err
 		}
 		v := b.Get(k)
+		if v == nil {
+			return notFound(id)
+		}
 		if err := proto.Unmarshal(v, &msg); err != nil {
 			return err
 		}
-		return nil
+		return ss.open(&msg)
 	})
 	if err != nil {
 		return nil, err
@@ -75,23 +141,181 @@ func (ss *MessageStore) Get(id ulid.ULID) (*message.Message, error) {
 		Provider: msg.Provider,
 		Content:  msg.Content,
 		Status:   msg.Status,
+		Client:   msg.Client,
+		Tenant:   msg.Tenant,
+		Revision: revision(&msg),
+
+		ContentType:  msg.ContentType,
+		TraceContext: msg.TraceContext,
+
+		Template:        msg.Template,
+		TemplateVersion: msg.TemplateVersion,
+		Locale:          msg.Locale,
+		Render:          msg.Render,
 	}, nil
 }
 
-// UpdateContent ...
-func (ss *MessageStore) UpdateContent(id ulid.ULID, content string) error {
+// GetHistory returns the status transitions of the message with the given
+// id, from the oldest to the newest.
+func (ss *MessageStore) GetHistory(id ulid.ULID) ([]*message.Transition, error) {
+	history := make([]*message.Transition, 0)
+	err := ss.Dst.DB.View(func(tx *bolt.Tx) error {
+		k, err := id.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		hb := db.Bucket(tx, ss.Tenant, db.HistoryBucket).Bucket(k)
+		if hb == nil {
+			// the history of the archived messages is kept with them.
+			if db.Bucket(tx, ss.Tenant, db.MsgBucket).Get(k) == nil &&
+				db.Bucket(tx, ss.Tenant, db.ArchiveBucket).Get(k) == nil {
+				return notFound(id)
+			}
+			return nil
+		}
+		return hb.ForEach(func(_, v []byte) error {
+			var tt pb.StatusTransition
+			if err := proto.Unmarshal(v, &tt); err != nil {
+				return err
+			}
+			history = append(history, new(message.Transition).FromProto(&tt))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetVersions returns the former contents of the message with the given
+// id, from the oldest to the newest.
+func (ss *MessageStore) GetVersions(id ulid.ULID) ([]*message.Version, error) {
+	versions := make([]*message.Version, 0)
+	err := ss.Dst.DB.View(func(tx *bolt.Tx) error {
+		k, err := id.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		vb := db.Bucket(tx, ss.Tenant, db.VersionsBucket).Bucket(k)
+		if vb == nil {
+			if db.Bucket(tx, ss.Tenant, db.MsgBucket).Get(k) == nil {
+				return notFound(id)
+			}
+			return nil
+		}
+		return vb.ForEach(func(_, v []byte) error {
+			var cv pb.ContentVersion
+			if err := proto.Unmarshal(v, &cv); err != nil {
+				return err
+			}
+			if err := ss.openVersion(id, &cv); err != nil {
+				return err
+			}
+			versions = append(versions, new(message.Version).FromProto(&cv))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// UpdateContent changes the content of the message at the given revision,
+// keeping the former one as a version replaced by client, and returns its
+// new revision. It fails with store.ErrConflict if the message is at
+// another revision, with a *message.StatusError if it can no longer be
+// updated or with store.ErrNotFound if it does not exist.
+func (ss *MessageStore) UpdateContent(id ulid.ULID, content string, rev int32, client string) (int32, error) {
 	var msg pb.Message
+	err := ss.Dst.DB.Update(func(tx *bolt.Tx) error {
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
+		k, err := id.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		v := b.Get(k)
+		if v == nil {
+			return notFound(id)
+		}
+		if err = proto.Unmarshal(v, &msg); err != nil {
+			return err
+		}
+		if err = checkRevision(id, &msg, rev); err != nil {
+			return err
+		}
+		if err = message.CheckUpdate(msg.Status); err != nil {
+			return err
+		}
+		// the former content is kept as it is stored, sealed if the
+		// encryption is enabled.
+		err = putVersion(tx, ss.Tenant, k, &pb.ContentVersion{
+			Revision:         revision(&msg),
+			Content:          msg.Content,
+			EncryptedContent: msg.EncryptedContent,
+			Time:             time.Now().UnixNano() / int64(time.Millisecond),
+			Client:           client,
+		})
+		if err != nil {
+			return err
+		}
+		msg.Content = content
+		msg.EncryptedContent = nil
+		msg.Revision = revision(&msg) + 1
+		// the new content replaces the variables of a template not
+		// rendered yet.
+		msg.Render = false
+		if err = ss.seal(&msg); err != nil {
+			return err
+		}
+		v, err = proto.Marshal(&msg)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, v)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return msg.Revision, nil
+}
+
+// Render replaces the variables of the message with the given id by the
+// content rendered from the version and locale of its template. It fails
+// with store.ErrConflict if the message is not waiting to be rendered,
+// the revision is not changed.
+func (ss *MessageStore) Render(id ulid.ULID, content, contentType string, version int32, locale string) error {
 	return ss.Dst.DB.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(db.MsgBucket)
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
 		k, err := id.MarshalBinary()
 		if err != nil {
 			return err
 		}
 		v := b.Get(k)
+		if v == nil {
+			return notFound(id)
+		}
+		var msg pb.Message
 		if err = proto.Unmarshal(v, &msg); err != nil {
 			return err
 		}
+		if !msg.Render {
+			return fmt.Errorf("message %s is rendered: %w", id, store.ErrConflict)
+		}
+
 		msg.Content = content
+		msg.EncryptedContent = nil
+		msg.ContentType = contentType
+		msg.TemplateVersion = version
+		msg.Locale = locale
+		msg.Render = false
+		if err = ss.seal(&msg); err != nil {
+			return err
+		}
 		v, err = proto.Marshal(&msg)
 		if err != nil {
 			return err
@@ -100,24 +324,693 @@ func (ss *MessageStore) UpdateContent(id ulid.ULID, content string) error {
 	})
 }
 
-// UpdateStatus ...
-func (ss *MessageStore) UpdateStatus(id ulid.ULID, status string) error {
+// UpdateStatus changes the status of the message to t.To and appends the
+// transition to its history. The previous status is filled by the store.
+//
+// The transition is checked against the current status in the same
+// transaction, failing with a *message.StatusError if it is not allowed
+// or with store.ErrNotFound if the message does not exist.
+func (ss *MessageStore) UpdateStatus(id ulid.ULID, t message.Transition) error {
+	return ss.UpdateStatusAt(id, 0, t)
+}
+
+// UpdateStatusAt is like UpdateStatus but fails with store.ErrConflict if
+// the message is not at the given revision, 0 accepts any.
+func (ss *MessageStore) UpdateStatusAt(id ulid.ULID, rev int32, t message.Transition) error {
 	var msg pb.Message
 	return ss.Dst.DB.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(db.MsgBucket)
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
 		k, err := id.MarshalBinary()
 		if err != nil {
 			return err
 		}
 		v := b.Get(k)
+		if v == nil {
+			return notFound(id)
+		}
 		if err = proto.Unmarshal(v, &msg); err != nil {
 			return err
 		}
-		msg.Status = string(status)
+		if rev != 0 {
+			if err = checkRevision(id, &msg, rev); err != nil {
+				return err
+			}
+		}
+		if err = message.CheckTransition(msg.Status, t.To); err != nil {
+			return err
+		}
+		t.From = msg.Status
+		if t.Provider == "" {
+			t.Provider = msg.Provider
+		}
+		msg.Status = string(t.To)
 		v, err = proto.Marshal(&msg)
 		if err != nil {
 			return err
 		}
-		return b.Put(k, v)
+		if err := b.Put(k, v); err != nil {
+			return err
+		}
+		return addTransition(tx, ss.Tenant, k, t)
 	})
 }
+
+// compactBatch is the number of messages checked by each transaction of
+// Compact, so the writers are not blocked while it sweeps the store.
+var compactBatch = 1000
+
+// Compact applies the retention policy over the stored messages and
+// returns the number of affected messages. The messages are checked in
+// batches of compactBatch, each one on its own transaction.
+func (ss *MessageStore) Compact(p *message.RetentionPolicy, now time.Time) (int, error) {
+	var (
+		n     int
+		after []byte
+	)
+	for {
+		var (
+			next []byte
+			c    int
+		)
+		err := ss.Dst.DB.Update(func(tx *bolt.Tx) error {
+			var err error
+			next, c, err = ss.compact(tx, p, now, after)
+			return err
+		})
+		if err != nil {
+			return n, err
+		}
+		n += c
+		if next == nil {
+			return n, nil
+		}
+		after = next
+	}
+}
+
+// compact applies the retention policy over up to compactBatch messages
+// with a key greater than after, or from the first one when after is nil.
+// It returns the key of the last checked message, nil when there are no
+// more messages, and the number of affected messages.
+func (ss *MessageStore) compact(tx *bolt.Tx, p *message.RetentionPolicy, now time.Time, after []byte) ([]byte, int, error) {
+	b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
+	hb := db.Bucket(tx, ss.Tenant, db.HistoryBucket)
+	vb := db.Bucket(tx, ss.Tenant, db.VersionsBucket)
+
+	type expired struct {
+		k   []byte
+		msg *pb.Message
+		r   *message.RetentionRule
+	}
+	var (
+		ee   []expired
+		last []byte
+	)
+
+	c := b.Cursor()
+	k, v := c.First()
+	if after != nil {
+		if k, v = c.Seek(after); k != nil && bytes.Equal(k, after) {
+			k, v = c.Next()
+		}
+	}
+	for i := 0; k != nil && i < compactBatch; k, v = c.Next() {
+		i++
+		last = append([]byte(nil), k...)
+
+		var msg pb.Message
+		if err := proto.Unmarshal(v, &msg); err != nil {
+			return nil, 0, err
+		}
+
+		var id ulid.ULID
+		if err := id.UnmarshalBinary(k); err != nil {
+			return nil, 0, err
+		}
+		updatedAt := ulid.Time(id.Time())
+		if h := hb.Bucket(k); h != nil {
+			if _, lt := h.Cursor().Last(); lt != nil {
+				var tt pb.StatusTransition
+				if err := proto.Unmarshal(lt, &tt); err != nil {
+					return nil, 0, err
+				}
+				updatedAt = new(message.Transition).FromProto(&tt).Time
+			}
+		}
+
+		m := &message.Message{
+			ID:      id,
+			Channel: msg.Channel,
+			Content: msg.Content,
+			Status:  msg.Status,
+		}
+		// the rules only check whether there is content, so the
+		// encrypted one is not decrypted.
+		if msg.EncryptedContent != nil {
+			m.Content = string(msg.EncryptedContent.Data)
+		}
+		if r := p.Rule(m, updatedAt, now); r != nil {
+			ee = append(ee, expired{last, &msg, r})
+		}
+	}
+
+	var n int
+	for _, e := range ee {
+		// the former contents go with the content on every action.
+		if vb.Bucket(e.k) != nil {
+			if err := vb.DeleteBucket(e.k); err != nil {
+				return nil, n, err
+			}
+		}
+
+		switch e.r.Action {
+		case message.RetentionStrip:
+			e.msg.Content = ""
+			e.msg.EncryptedContent = nil
+			v, err := proto.Marshal(e.msg)
+			if err != nil {
+				return nil, n, err
+			}
+			if err := b.Put(e.k, v); err != nil {
+				return nil, n, err
+			}
+		case message.RetentionArchive:
+			if err := archive(tx, ss.Tenant, e.k, e.msg); err != nil {
+				return nil, n, err
+			}
+			fallthrough
+		case message.RetentionDelete:
+			if err := b.Delete(e.k); err != nil {
+				return nil, n, err
+			}
+			if hb.Bucket(e.k) != nil {
+				if err := hb.DeleteBucket(e.k); err != nil {
+					return nil, n, err
+				}
+			}
+		default:
+			return nil, n, fmt.Errorf("unknown retention action %q", e.r.Action)
+		}
+		n++
+	}
+
+	return last, n, nil
+}
+
+// archive stores msg with its history in the archive bucket.
+func archive(tx *bolt.Tx, tenantID string, k []byte, msg *pb.Message) error {
+	if h := db.Bucket(tx, tenantID, db.HistoryBucket).Bucket(k); h != nil {
+		err := h.ForEach(func(_, v []byte) error {
+			var tt pb.StatusTransition
+			if err := proto.Unmarshal(v, &tt); err != nil {
+				return err
+			}
+			msg.History = append(msg.History, &tt)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	v, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return db.Bucket(tx, tenantID, db.ArchiveBucket).Put(k, v)
+}
+
+// ForEach calls fn for every stored message with its history, from the
+// oldest to the newest ULID. When archived is true the archived messages
+// are included.
+//
+// The iteration stops at the first error returned by fn.
+func (ss *MessageStore) ForEach(archived bool, fn func(m *message.Message, history []*message.Transition) error) error {
+	return ss.Dst.DB.View(func(tx *bolt.Tx) error {
+		hb := db.Bucket(tx, ss.Tenant, db.HistoryBucket)
+
+		err := db.Bucket(tx, ss.Tenant, db.MsgBucket).ForEach(func(k, v []byte) error {
+			var msg pb.Message
+			if err := proto.Unmarshal(v, &msg); err != nil {
+				return err
+			}
+			if h := hb.Bucket(k); h != nil {
+				err := h.ForEach(func(_, v []byte) error {
+					var tt pb.StatusTransition
+					if err := proto.Unmarshal(v, &tt); err != nil {
+						return err
+					}
+					msg.History = append(msg.History, &tt)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if err := ss.open(&msg); err != nil {
+				return err
+			}
+			return forEachFn(&msg, fn)
+		})
+		if err != nil || !archived {
+			return err
+		}
+
+		return db.Bucket(tx, ss.Tenant, db.ArchiveBucket).ForEach(func(_, v []byte) error {
+			var msg pb.Message
+			if err := proto.Unmarshal(v, &msg); err != nil {
+				return err
+			}
+			if err := ss.open(&msg); err != nil {
+				return err
+			}
+			return forEachFn(&msg, fn)
+		})
+	})
+}
+
+// Record is a stored message with its history, as returned by Page.
+type Record struct {
+	Message *message.Message
+	History []*message.Transition
+
+	// Sealed is the encrypted content of the message when the encryption
+	// is enabled, the content of Message is empty then.
+	Sealed *encryption.Envelope
+}
+
+// Page returns up to limit messages with an id greater than after, from
+// the oldest to the newest, of the stored messages or of the archived ones
+// when archived is true. The zero id starts from the first one, and the
+// id of the last returned message continues from it.
+//
+// Unlike ForEach, the transaction is closed before returning, so the
+// messages can be processed without holding it, and the content is not
+// decrypted: when the encryption is enabled it is returned sealed, even
+// the one stored before enabling it.
+func (ss *MessageStore) Page(archived bool, after ulid.ULID, limit int) ([]*Record, error) {
+	var records []*Record
+	err := ss.Dst.DB.View(func(tx *bolt.Tx) error {
+		name := db.MsgBucket
+		if archived {
+			name = db.ArchiveBucket
+		}
+		hb := db.Bucket(tx, ss.Tenant, db.HistoryBucket)
+
+		ak, err := after.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		c := db.Bucket(tx, ss.Tenant, name).Cursor()
+		k, v := c.Seek(ak)
+		if k != nil && after != (ulid.ULID{}) && bytes.Equal(k, ak) {
+			k, v = c.Next()
+		}
+		for ; k != nil && len(records) < limit; k, v = c.Next() {
+			var msg pb.Message
+			if err := proto.Unmarshal(v, &msg); err != nil {
+				return err
+			}
+			// the history of the archived messages is kept with them.
+			if h := hb.Bucket(k); h != nil && !archived {
+				err := h.ForEach(func(_, v []byte) error {
+					var tt pb.StatusTransition
+					if err := proto.Unmarshal(v, &tt); err != nil {
+						return err
+					}
+					msg.History = append(msg.History, &tt)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if msg.EncryptedContent == nil {
+				if err := ss.seal(&msg); err != nil {
+					return err
+				}
+			}
+
+			err := forEachFn(&msg, func(m *message.Message, history []*message.Transition) error {
+				r := &Record{
+					Message: m,
+					History: history,
+				}
+				if msg.EncryptedContent != nil {
+					r.Sealed = envelopeFromProto(msg.EncryptedContent)
+				}
+				records = append(records, r)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func forEachFn(msg *pb.Message, fn func(m *message.Message, history []*message.Transition) error) error {
+	m, err := new(message.Message).FromProto(msg)
+	if err != nil {
+		return err
+	}
+
+	history := make([]*message.Transition, 0, len(msg.History))
+	for _, tt := range msg.History {
+		history = append(history, new(message.Transition).FromProto(tt))
+	}
+
+	return fn(m, history)
+}
+
+// Restore stores the message of r with its history as an archived message,
+// replacing any archived message with the same id. It is meant to restore
+// exported messages, which are kept apart from the live ones so they are
+// never approved, queued or delivered again. The sealed content is kept as
+// it is. It fails with store.ErrConflict if the message is still stored.
+func (ss *MessageStore) Restore(r *Record) error {
+	m := r.Message
+	return ss.Dst.DB.Update(func(tx *bolt.Tx) error {
+		k, err := m.ID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if db.Bucket(tx, ss.Tenant, db.MsgBucket).Get(k) != nil {
+			return fmt.Errorf("message %s is stored: %w", m.ID, store.ErrConflict)
+		}
+
+		msg := &pb.Message{
+			Id:       m.ID.String(),
+			Channel:  m.Channel,
+			Provider: m.Provider,
+			Content:  m.Content,
+			Status:   m.Status,
+			Client:   m.Client,
+			Tenant:   m.Tenant,
+			Revision: m.Revision,
+
+			ContentType:  m.ContentType,
+			TraceContext: m.TraceContext,
+
+			Template:        m.Template,
+			TemplateVersion: m.TemplateVersion,
+			Locale:          m.Locale,
+			Render:          m.Render,
+		}
+		for _, t := range r.History {
+			msg.History = append(msg.History, t.ToProto())
+		}
+		if env := r.Sealed; env != nil {
+			msg.EncryptedContent = &pb.Envelope{
+				KeyId: env.KeyID,
+				Key:   env.Key,
+				Nonce: env.Nonce,
+				Data:  env.Data,
+			}
+		} else if err := ss.seal(msg); err != nil {
+			return err
+		}
+		v, err := proto.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		return db.Bucket(tx, ss.Tenant, db.ArchiveBucket).Put(k, v)
+	})
+}
+
+// Rotate encrypts again the content of the messages, including the
+// archived ones, that was not encrypted with the current key or was kept
+// in plaintext, and returns the number of rewritten messages.
+func (ss *MessageStore) Rotate() (int, error) {
+	if ss.Encrypter == nil {
+		return 0, errors.New("encryption is not configured")
+	}
+
+	var n int
+	err := ss.Dst.DB.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{db.MsgBucket, db.ArchiveBucket} {
+			b := db.Bucket(tx, ss.Tenant, name)
+
+			type record struct {
+				k, v []byte
+			}
+			var rr []record
+
+			err := b.ForEach(func(k, v []byte) error {
+				var msg pb.Message
+				if err := proto.Unmarshal(v, &msg); err != nil {
+					return err
+				}
+				if msg.EncryptedContent == nil && msg.Content == "" {
+					return nil
+				}
+				if msg.EncryptedContent != nil && !ss.Encrypter.Stale(envelopeFromProto(msg.EncryptedContent)) {
+					return nil
+				}
+
+				if err := ss.open(&msg); err != nil {
+					return err
+				}
+				if err := ss.seal(&msg); err != nil {
+					return err
+				}
+				v, err := proto.Marshal(&msg)
+				if err != nil {
+					return err
+				}
+				rr = append(rr, record{append([]byte(nil), k...), v})
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, r := range rr {
+				if err := b.Put(r.k, r.v); err != nil {
+					return err
+				}
+			}
+			n += len(rr)
+		}
+
+		nv, err := ss.rotateVersions(tx)
+		n += nv
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// rotateVersions encrypts again the former contents as Rotate, and
+// returns the number of rewritten ones.
+func (ss *MessageStore) rotateVersions(tx *bolt.Tx) (int, error) {
+	b := db.Bucket(tx, ss.Tenant, db.VersionsBucket)
+
+	// the nested buckets are rewritten after listing them, as the bucket
+	// cannot be modified while iterating it.
+	var keys [][]byte
+	err := b.ForEach(func(k, _ []byte) error {
+		keys = append(keys, append([]byte(nil), k...))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, k := range keys {
+		vb := b.Bucket(k)
+		if vb == nil {
+			continue
+		}
+
+		type record struct {
+			k, v []byte
+		}
+		var rr []record
+
+		err := vb.ForEach(func(rk, v []byte) error {
+			var cv pb.ContentVersion
+			if err := proto.Unmarshal(v, &cv); err != nil {
+				return err
+			}
+			if cv.EncryptedContent == nil && cv.Content == "" {
+				return nil
+			}
+			if cv.EncryptedContent != nil && !ss.Encrypter.Stale(envelopeFromProto(cv.EncryptedContent)) {
+				return nil
+			}
+
+			msg := &pb.Message{Content: cv.Content, EncryptedContent: cv.EncryptedContent}
+			if err := ss.open(msg); err != nil {
+				return err
+			}
+			if err := ss.seal(msg); err != nil {
+				return err
+			}
+			cv.Content, cv.EncryptedContent = msg.Content, msg.EncryptedContent
+			v, err := proto.Marshal(&cv)
+			if err != nil {
+				return err
+			}
+			rr = append(rr, record{append([]byte(nil), rk...), v})
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, r := range rr {
+			if err := vb.Put(r.k, r.v); err != nil {
+				return 0, err
+			}
+		}
+		n += len(rr)
+	}
+
+	return n, nil
+}
+
+// seal moves the content of msg to its encrypted content when the
+// encryption is enabled, bound to the id of msg.
+func (ss *MessageStore) seal(msg *pb.Message) error {
+	if ss.Encrypter == nil || msg.Content == "" {
+		return nil
+	}
+
+	env, err := ss.Encrypter.Encrypt([]byte(msg.Content), []byte(msg.Id))
+	if err != nil {
+		return err
+	}
+	msg.EncryptedContent = &pb.Envelope{
+		KeyId: env.KeyID,
+		Key:   env.Key,
+		Nonce: env.Nonce,
+		Data:  env.Data,
+	}
+	msg.Content = ""
+
+	return nil
+}
+
+// open decrypts the encrypted content of msg into its content.
+func (ss *MessageStore) open(msg *pb.Message) error {
+	if msg.EncryptedContent == nil {
+		return nil
+	}
+	if ss.Encrypter == nil {
+		return fmt.Errorf("message %s is encrypted and encryption is not configured", msg.Id)
+	}
+
+	b, err := ss.Encrypter.Decrypt(envelopeFromProto(msg.EncryptedContent), []byte(msg.Id))
+	if err != nil {
+		return fmt.Errorf("message %s: %v", msg.Id, err)
+	}
+	msg.Content = string(b)
+	msg.EncryptedContent = nil
+
+	return nil
+}
+
+func envelopeFromProto(env *pb.Envelope) *encryption.Envelope {
+	return &encryption.Envelope{
+		KeyID: env.KeyId,
+		Key:   env.Key,
+		Nonce: env.Nonce,
+		Data:  env.Data,
+	}
+}
+
+// notFound is the error of the unknown message id.
+func notFound(id ulid.ULID) error {
+	return fmt.Errorf("message %s: %w", id, store.ErrNotFound)
+}
+
+// addTransition appends t to the history of the message with key k.
+func addTransition(tx *bolt.Tx, tenantID string, k []byte, t message.Transition) error {
+	hb, err := db.Bucket(tx, tenantID, db.HistoryBucket).CreateBucketIfNotExists(k)
+	if err != nil {
+		return err
+	}
+
+	seq, err := hb.NextSequence()
+	if err != nil {
+		return err
+	}
+	sk := make([]byte, 8)
+	binary.BigEndian.PutUint64(sk, seq)
+
+	if t.Time.IsZero() {
+		t.Time = time.Now()
+	}
+	v, err := proto.Marshal(t.ToProto())
+	if err != nil {
+		return err
+	}
+
+	return hb.Put(sk, v)
+}
+
+// revision returns the revision of msg, the messages stored before the
+// revisions are at the first one.
+func revision(msg *pb.Message) int32 {
+	if msg.Revision == 0 {
+		return 1
+	}
+
+	return msg.Revision
+}
+
+// checkRevision fails with store.ErrConflict if msg is not at rev.
+func checkRevision(id ulid.ULID, msg *pb.Message, rev int32) error {
+	if cur := revision(msg); cur != rev {
+		return fmt.Errorf("message %s is at revision %d, not %d: %w", id, cur, rev, store.ErrConflict)
+	}
+
+	return nil
+}
+
+// putVersion keeps cv as a former content of the message with key k.
+func putVersion(tx *bolt.Tx, tenantID string, k []byte, cv *pb.ContentVersion) error {
+	vb, err := db.Bucket(tx, tenantID, db.VersionsBucket).CreateBucketIfNotExists(k)
+	if err != nil {
+		return err
+	}
+
+	rk := make([]byte, 4)
+	binary.BigEndian.PutUint32(rk, uint32(cv.Revision))
+	v, err := proto.Marshal(cv)
+	if err != nil {
+		return err
+	}
+
+	return vb.Put(rk, v)
+}
+
+// openVersion decrypts the encrypted content of cv into its content.
+func (ss *MessageStore) openVersion(id ulid.ULID, cv *pb.ContentVersion) error {
+	msg := &pb.Message{
+		Id:               id.String(),
+		EncryptedContent: cv.EncryptedContent,
+	}
+	if err := ss.open(msg); err != nil {
+		return err
+	}
+	if cv.EncryptedContent != nil {
+		cv.Content = msg.Content
+		cv.EncryptedContent = nil
+	}
+
+	return nil
+}
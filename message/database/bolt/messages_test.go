@@ -0,0 +1,353 @@
+package bolt
+
+import (
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/store"
+	"github.com/oklog/ulid"
+
+	db "github.com/microapis/messages-core/message/database"
+	pb "github.com/microapis/messages-core/proto"
+)
+
+func newMessageStore(t *testing.T) *MessageStore {
+	t.Helper()
+	dst, err := db.NewBoltDatastore(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dst.DB.Close() })
+
+	ss, err := NewMessageStore(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+func addMessage(t *testing.T, ss *MessageStore, m message.Message) ulid.ULID {
+	t.Helper()
+	m.ID = ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader)
+	if err := ss.AddMessage(m, message.SourceAPI); err != nil {
+		t.Fatal(err)
+	}
+	return m.ID
+}
+
+func TestUpdateContent(t *testing.T) {
+	ss := newMessageStore(t)
+	id := addMessage(t, ss, message.Message{Channel: "email", Content: "v1"})
+
+	tests := []struct {
+		name    string
+		id      ulid.ULID
+		content string
+		rev     int32
+		want    int32
+		err     error
+	}{
+		{"first revision", id, "v2", 1, 2, nil},
+		{"stale revision", id, "v3", 1, 0, store.ErrConflict},
+		{"future revision", id, "v3", 3, 0, store.ErrConflict},
+		{"current revision", id, "v3", 2, 3, nil},
+		{"unknown message", ulid.MustNew(ulid.Now(), rand.Reader), "v4", 1, 0, store.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		rev, err := ss.UpdateContent(tt.id, tt.content, tt.rev, "client")
+		if !errors.Is(err, tt.err) || rev != tt.want {
+			t.Errorf("%s: UpdateContent() = %d, %v, want %d, %v", tt.name, rev, err, tt.want, tt.err)
+		}
+	}
+
+	m, err := ss.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Content != "v3" || m.Revision != 3 {
+		t.Errorf("Get() = %q at revision %d, want %q at revision 3", m.Content, m.Revision, "v3")
+	}
+
+	versions, err := ss.GetVersions(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Content != "v1" || versions[1].Content != "v2" {
+		t.Errorf("GetVersions() = %v, want v1 and v2", versions)
+	}
+
+	// the content can not change once the message is being delivered.
+	for _, s := range []string{message.Approved, message.Sending} {
+		if err := ss.UpdateStatus(id, message.Transition{To: s, Source: message.SourceScheduler}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var se *message.StatusError
+	if _, err := ss.UpdateContent(id, "v4", 3, "client"); !errors.As(err, &se) {
+		t.Errorf("UpdateContent() while sending = %v, want a *message.StatusError", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ss := newMessageStore(t)
+	id := addMessage(t, ss, message.Message{Channel: "email", Content: "content", Client: "a"})
+
+	transitions := []message.Transition{
+		{To: message.Approved, Source: message.SourceAPI, Client: "a"},
+		{To: message.Sending, Source: message.SourceScheduler, Provider: "smtp", Attempt: 1},
+		{To: message.FailedDeliver, Source: message.SourceScheduler, Provider: "smtp", Attempt: 1, Error: "timeout"},
+		{To: message.Sending, Source: message.SourceScheduler, Provider: "smtp", Attempt: 2},
+		{To: message.Sent, Source: message.SourceScheduler, Provider: "smtp", Attempt: 2},
+	}
+	for _, tr := range transitions {
+		if err := ss.UpdateStatus(id, tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a transition not allowed is not recorded.
+	var se *message.StatusError
+	if err := ss.UpdateStatus(id, message.Transition{To: message.Cancelled, Source: message.SourceAPI}); !errors.As(err, &se) {
+		t.Errorf("UpdateStatus() of a sent message = %v, want a *message.StatusError", err)
+	}
+
+	history, err := ss.GetHistory(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(transitions)+1 {
+		t.Fatalf("GetHistory() = %d transitions, want %d", len(history), len(transitions)+1)
+	}
+	if h := history[0]; h.From != "" || h.To != message.Received || h.Source != message.SourceAPI {
+		t.Errorf("GetHistory()[0] = %+v, want the reception", h)
+	}
+	from := message.Received
+	for i, want := range transitions {
+		h := history[i+1]
+		if h.From != from || h.To != want.To || h.Source != want.Source || h.Client != want.Client ||
+			h.Provider != want.Provider || h.Attempt != want.Attempt || h.Error != want.Error {
+			t.Errorf("GetHistory()[%d] = %+v, want %+v from %s", i+1, h, want, from)
+		}
+		if h.Time.Before(history[i].Time) || h.Time.IsZero() {
+			t.Errorf("GetHistory()[%d] at %v, before the former transition at %v", i+1, h.Time, history[i].Time)
+		}
+		from = want.To
+	}
+
+	if _, err := ss.GetHistory(ulid.MustNew(ulid.Now(), rand.Reader)); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetHistory() of an unknown message = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+func TestRender(t *testing.T) {
+	ss := newMessageStore(t)
+	id := addMessage(t, ss, message.Message{Channel: "email", Content: `{"name":"Ana"}`, Template: "welcome", Render: true})
+
+	if err := ss.Render(id, "Hi Ana", "text/plain", 2, "es"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ss.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Render || m.Content != "Hi Ana" || m.TemplateVersion != 2 || m.Locale != "es" || m.Revision != 1 {
+		t.Errorf("Get() = %+v, want the rendered content at revision 1", m)
+	}
+
+	if err := ss.Render(id, "Hi again", "text/plain", 2, "es"); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("Render() of a rendered message = %v, want %v", err, store.ErrConflict)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	defer func(n int) { compactBatch = n }(compactBatch)
+	compactBatch = 2
+
+	ss := newMessageStore(t)
+	statuses := map[string][]string{
+		"sent":      {message.Approved, message.Sending, message.Sent},
+		"cancelled": {message.Cancelled},
+		"approved":  {message.Approved},
+		"stripped":  {message.Approved, message.Sending, message.FailedDeliver},
+		"sent2":     {message.Approved, message.Sending, message.Sent},
+	}
+	ids := make(map[string]ulid.ULID)
+	for name, transitions := range statuses {
+		channel := "email"
+		if name == "stripped" {
+			channel = "sms"
+		}
+		id := addMessage(t, ss, message.Message{Channel: channel, Content: name})
+		for _, s := range transitions {
+			if err := ss.UpdateStatus(id, message.Transition{To: s, Source: message.SourceScheduler}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids[name] = id
+	}
+
+	const day = 24 * time.Hour
+	p := &message.RetentionPolicy{Rules: []message.RetentionRule{
+		{Status: message.Sent, MaxAge: 30 * day, Action: message.RetentionDelete},
+		{Channel: "sms", MaxAge: day, Action: message.RetentionStrip},
+		{MaxAge: 7 * day, Action: message.RetentionArchive},
+	}}
+
+	tests := []struct {
+		age     time.Duration
+		n       int
+		removed []string
+		content map[string]string
+	}{
+		{2 * day, 1, nil, map[string]string{"stripped": "", "sent": "sent"}},
+		{8 * day, 2, []string{"cancelled", "stripped"}, map[string]string{"sent": "sent", "approved": "approved"}},
+		{31 * day, 2, []string{"sent", "sent2"}, map[string]string{"approved": "approved"}},
+	}
+
+	for _, tt := range tests {
+		n, err := ss.Compact(p, time.Now().Add(tt.age))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tt.n {
+			t.Errorf("Compact() after %v = %d, want %d", tt.age, n, tt.n)
+		}
+		for _, name := range tt.removed {
+			if _, err := ss.Get(ids[name]); !errors.Is(err, store.ErrNotFound) {
+				t.Errorf("Get(%s) after %v = %v, want %v", name, tt.age, err, store.ErrNotFound)
+			}
+		}
+		for name, content := range tt.content {
+			m, err := ss.Get(ids[name])
+			if err != nil {
+				t.Errorf("Get(%s) after %v: %v", name, tt.age, err)
+			} else if m.Content != content {
+				t.Errorf("Get(%s) after %v = %q, want %q", name, tt.age, m.Content, content)
+			}
+		}
+	}
+
+	// the archived messages are kept with their history.
+	archived, err := ss.Page(true, ulid.ULID{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 2 {
+		t.Errorf("Page(archived) = %d messages, want 2", len(archived))
+	}
+}
+
+func TestEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	encrypter := func(keyID string) *encryption.Encrypter {
+		t.Helper()
+		if err := encryption.AddKey(path, keyID); err != nil {
+			t.Fatal(err)
+		}
+		p, err := encryption.NewLocalKeyProvider(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encryption.New(p)
+	}
+
+	ss := newMessageStore(t)
+	ss.Encrypter = encrypter("k1")
+	first := addMessage(t, ss, message.Message{Channel: "email", Content: "first"})
+	second := addMessage(t, ss, message.Message{Channel: "email", Content: "second"})
+
+	ss.Encrypter = encrypter("k2")
+	if n, err := ss.Rotate(); err != nil || n != 2 {
+		t.Errorf("Rotate() = %d, %v, want 2", n, err)
+	}
+	if n, err := ss.Rotate(); err != nil || n != 0 {
+		t.Errorf("Rotate() again = %d, %v, want 0", n, err)
+	}
+	for id, content := range map[ulid.ULID]string{first: "first", second: "second"} {
+		m, err := ss.Get(id)
+		if err != nil || m.Content != content {
+			t.Errorf("Get() after Rotate() = %v, %v, want %q", m, err, content)
+		}
+	}
+
+	// the content of first copied to second is not decrypted.
+	err := ss.Dst.DB.Update(func(tx *bolt.Tx) error {
+		b := db.Bucket(tx, ss.Tenant, db.MsgBucket)
+		var src, dst pb.Message
+		if err := proto.Unmarshal(b.Get(first[:]), &src); err != nil {
+			return err
+		}
+		if err := proto.Unmarshal(b.Get(second[:]), &dst); err != nil {
+			return err
+		}
+		dst.EncryptedContent = src.EncryptedContent
+		v, err := proto.Marshal(&dst)
+		if err != nil {
+			return err
+		}
+		return b.Put(second[:], v)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err := ss.Get(second); err == nil {
+		t.Errorf("Get() of a copied content = %q, want an error", m.Content)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	ss := newMessageStore(t)
+	unknown := ulid.MustNew(ulid.Now(), rand.Reader)
+
+	calls := map[string]func() error{
+		"Get":           func() error { _, err := ss.Get(unknown); return err },
+		"GetHistory":    func() error { _, err := ss.GetHistory(unknown); return err },
+		"GetVersions":   func() error { _, err := ss.GetVersions(unknown); return err },
+		"UpdateContent": func() error { _, err := ss.UpdateContent(unknown, "v2", 1, "client"); return err },
+		"UpdateStatus": func() error {
+			return ss.UpdateStatus(unknown, message.Transition{To: message.Cancelled, Source: message.SourceAPI})
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("%s() = %v, want %v", name, err, store.ErrNotFound)
+		}
+	}
+
+	ts, err := NewTemplateStore(ss.Dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Get("email", "welcome", "en", 0); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("template Get() = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+func TestConflict(t *testing.T) {
+	ss := newMessageStore(t)
+	m := message.Message{ID: ulid.MustNew(ulid.Now(), rand.Reader), Channel: "email", Content: "v1"}
+	if err := ss.AddMessage(m, message.SourceAPI); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ss.AddMessage(m, message.SourceAPI); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("AddMessage() twice = %v, want %v", err, store.ErrConflict)
+	}
+
+	err := ss.UpdateStatusAt(m.ID, 2, message.Transition{To: message.Cancelled, Source: message.SourceAPI})
+	if !errors.Is(err, store.ErrConflict) {
+		t.Errorf("UpdateStatusAt() at another revision = %v, want %v", err, store.ErrConflict)
+	}
+	if got, err := ss.Get(m.ID); err != nil || got.Status == message.Cancelled {
+		t.Errorf("Get() = %v, %v, want the message not cancelled", got, err)
+	}
+}
@@ -0,0 +1,235 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	pb "github.com/microapis/messages-core/proto"
+	"github.com/microapis/messages-core/store"
+	"github.com/microapis/messages-core/templates"
+	"google.golang.org/protobuf/proto"
+
+	db "github.com/microapis/messages-core/message/database"
+)
+
+// TemplateStore keeps the versions of the templates of the channels. The
+// versions of the variant without locale are kept on the bucket of the
+// template, and the ones of each locale on a nested bucket named after it.
+type TemplateStore struct {
+	Dst *db.BoltDatastore
+
+	// Tenant owns the templates of the store, empty for the default
+	// tenant.
+	Tenant string
+}
+
+// NewTemplateStore ...
+func NewTemplateStore(dst *db.BoltDatastore) (*TemplateStore, error) {
+	return &TemplateStore{
+		Dst: dst,
+	}, nil
+}
+
+// For returns a store of the templates of tenant, creating its bucket if
+// it does not exist.
+func (ts *TemplateStore) For(tenantID string) (*TemplateStore, error) {
+	if err := ts.Dst.CreateBuckets(tenantID, db.TemplatesBucket); err != nil {
+		return nil, err
+	}
+
+	return &TemplateStore{
+		Dst:    ts.Dst,
+		Tenant: tenantID,
+	}, nil
+}
+
+// Create stores t as the next version of its template, and returns it
+// with its version and time.
+func (ts *TemplateStore) Create(t templates.Template) (*templates.Template, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := ts.Dst.DB.Update(func(tx *bolt.Tx) error {
+		cb, err := db.Bucket(tx, ts.Tenant, db.TemplatesBucket).CreateBucketIfNotExists([]byte(t.Channel))
+		if err != nil {
+			return err
+		}
+		b, err := cb.CreateBucketIfNotExists([]byte(t.Name))
+		if err != nil {
+			return err
+		}
+		if t.Locale != "" {
+			if b, err = b.CreateBucketIfNotExists([]byte(t.Locale)); err != nil {
+				return err
+			}
+		}
+
+		t.Version = 1
+		if k, _ := last(b); k != nil {
+			t.Version = int32(binary.BigEndian.Uint32(k)) + 1
+		}
+		t.Time = time.Now()
+
+		v, err := proto.Marshal(t.ToProto())
+		if err != nil {
+			return err
+		}
+		return b.Put(versionKey(t.Version), v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Get returns the given version of the variant of the template, or the
+// latest one when version is 0, failing with store.ErrNotFound if it does
+// not exist.
+func (ts *TemplateStore) Get(channel, name, locale string, version int32) (*templates.Template, error) {
+	return ts.Resolve(channel, name, []string{locale}, version)
+}
+
+// Resolve returns the given version, or the latest one when version is 0,
+// of the first variant of the template found on locales. The version is
+// not looked for on the next variants when the first one found lacks it.
+func (ts *TemplateStore) Resolve(channel, name string, locales []string, version int32) (*templates.Template, error) {
+	var t *templates.Template
+	err := ts.Dst.DB.View(func(tx *bolt.Tx) error {
+		for _, locale := range locales {
+			b := ts.bucket(tx, channel, name, locale)
+			if b == nil {
+				continue
+			}
+			k, v := last(b)
+			if k == nil {
+				continue
+			}
+
+			if version != 0 {
+				if v = b.Get(versionKey(version)); v == nil {
+					return templateNotFound(channel, name, locale, version)
+				}
+			}
+
+			var pt pb.Template
+			if err := proto.Unmarshal(v, &pt); err != nil {
+				return err
+			}
+			t = new(templates.Template).FromProto(&pt)
+			return nil
+		}
+
+		if len(locales) == 1 {
+			return templateNotFound(channel, name, locales[0], version)
+		}
+		return templateNotFound(channel, name, "", 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Versions returns every version of the variant of the template, from the
+// oldest to the newest, failing with store.ErrNotFound if it does not
+// exist.
+func (ts *TemplateStore) Versions(channel, name, locale string) ([]*templates.Template, error) {
+	var versions []*templates.Template
+	err := ts.Dst.DB.View(func(tx *bolt.Tx) error {
+		b := ts.bucket(tx, channel, name, locale)
+		if b == nil {
+			return templateNotFound(channel, name, locale, 0)
+		}
+
+		return b.ForEach(func(_, v []byte) error {
+			// the variants with a locale are nested buckets.
+			if v == nil {
+				return nil
+			}
+
+			var pt pb.Template
+			if err := proto.Unmarshal(v, &pt); err != nil {
+				return err
+			}
+			versions = append(versions, new(templates.Template).FromProto(&pt))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, templateNotFound(channel, name, locale, 0)
+	}
+
+	return versions, nil
+}
+
+// Rollback stores the given version of the variant of the template as the
+// next one, on behalf of client, and returns it.
+func (ts *TemplateStore) Rollback(channel, name, locale string, version int32, client string) (*templates.Template, error) {
+	if version == 0 {
+		return nil, errors.New("the version to roll back to is required")
+	}
+
+	t, err := ts.Get(channel, name, locale, version)
+	if err != nil {
+		return nil, err
+	}
+	t.Client = client
+	t.RollbackOf = version
+
+	return ts.Create(*t)
+}
+
+func (ts *TemplateStore) bucket(tx *bolt.Tx, channel, name, locale string) *bolt.Bucket {
+	cb := db.Bucket(tx, ts.Tenant, db.TemplatesBucket).Bucket([]byte(channel))
+	if cb == nil {
+		return nil
+	}
+	b := cb.Bucket([]byte(name))
+	if b == nil || locale == "" {
+		return b
+	}
+
+	return b.Bucket([]byte(locale))
+}
+
+// last returns the latest version of b, skipping the nested buckets of the
+// variants with a locale, which sort after the versions.
+func last(b *bolt.Bucket) ([]byte, []byte) {
+	c := b.Cursor()
+	k, v := c.Last()
+	for k != nil && v == nil {
+		k, v = c.Prev()
+	}
+
+	return k, v
+}
+
+func versionKey(version int32) []byte {
+	k := make([]byte, 4)
+	binary.BigEndian.PutUint32(k, uint32(version))
+
+	return k
+}
+
+// templateNotFound is the error of an unknown template, variant or
+// version.
+func templateNotFound(channel, name, locale string, version int32) error {
+	id := channel + "/" + name
+	if locale != "" {
+		id += " (" + locale + ")"
+	}
+	if version == 0 {
+		return fmt.Errorf("template %s: %w", id, store.ErrNotFound)
+	}
+
+	return fmt.Errorf("template %s version %d: %w", id, version, store.ErrNotFound)
+}
@@ -1,9 +1,11 @@
 package db
 
 import (
+	"errors"
 	"os"
 
 	"github.com/boltdb/bolt"
+	"github.com/microapis/messages-core/tenant"
 )
 
 // BoltDatastore store data in db using bolt as a db backend
@@ -14,6 +16,19 @@ type BoltDatastore struct {
 var (
 	// MsgBucket ...
 	MsgBucket = []byte("messages")
+	// HistoryBucket ...
+	HistoryBucket = []byte("history")
+	// ArchiveBucket ...
+	ArchiveBucket = []byte("archive")
+	// VersionsBucket keeps a nested bucket per message with its former
+	// contents by revision.
+	VersionsBucket = []byte("versions")
+	// TemplatesBucket keeps a nested bucket per channel, with a nested
+	// bucket per template with its versions.
+	TemplatesBucket = []byte("templates")
+	// TenantsBucket keeps a nested bucket per tenant, other than the
+	// default one, with the buckets of the tenant.
+	TenantsBucket = []byte("tenants")
 )
 
 // NewBoltDatastore returns a new datastore instance or an error if
@@ -24,15 +39,89 @@ func NewBoltDatastore(path string) (*BoltDatastore, error) {
 		return nil, err
 	}
 
-	err = db.Update(func(tx *bolt.Tx) error {
-		_, berr := tx.CreateBucketIfNotExists(MsgBucket)
-		return berr
+	dst := &BoltDatastore{
+		DB: db,
+	}
+
+	err = dst.CreateBuckets(tenant.Default, MsgBucket, HistoryBucket, ArchiveBucket, VersionsBucket, TemplatesBucket, TenantsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// CreateBuckets creates the buckets of tenant with the given names, if
+// they do not exist.
+func (dst *BoltDatastore) CreateBuckets(tenantID string, names ...[]byte) error {
+	return dst.DB.Update(func(tx *bolt.Tx) error {
+		var parent interface {
+			CreateBucketIfNotExists([]byte) (*bolt.Bucket, error)
+		} = tx
+		if tenantID != tenant.Default {
+			tb, err := tx.CreateBucketIfNotExists(TenantsBucket)
+			if err != nil {
+				return err
+			}
+			if parent, err = tb.CreateBucketIfNotExists([]byte(tenantID)); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range names {
+			if _, err := parent.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Bucket returns the bucket of tenant with the given name, or nil if it
+// does not exist.
+func Bucket(tx *bolt.Tx, tenantID string, name []byte) *bolt.Bucket {
+	if tenantID == tenant.Default {
+		return tx.Bucket(name)
+	}
+
+	tb := tx.Bucket(TenantsBucket)
+	if tb == nil {
+		return nil
+	}
+	if tb = tb.Bucket([]byte(tenantID)); tb == nil {
+		return nil
+	}
+
+	return tb.Bucket(name)
+}
+
+// Tenants returns the ids of the tenants with buckets, other than the
+// default one.
+func (dst *BoltDatastore) Tenants() ([]string, error) {
+	var tenants []string
+	err := dst.DB.View(func(tx *bolt.Tx) error {
+		tb := tx.Bucket(TenantsBucket)
+		if tb == nil {
+			return nil
+		}
+		return tb.ForEach(func(k, _ []byte) error {
+			tenants = append(tenants, string(k))
+			return nil
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &BoltDatastore{
-		DB: db,
-	}, nil
+	return tenants, nil
+}
+
+// Ping reports whether the db is open and holds the messages bucket.
+func (dst *BoltDatastore) Ping() error {
+	return dst.DB.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(MsgBucket) == nil {
+			return errors.New("messages bucket not found")
+		}
+		return nil
+	})
 }
@@ -7,8 +7,16 @@ import (
 
 // Statuses ...
 const (
-	// Pending ...
+	// Received is the status of a new message, waiting to be approved.
+	Received = "received"
+	// Pending is the status of the messages stored by the older versions,
+	// which stored them once approved and queued, so they are delivered
+	// as the approved ones.
 	Pending = "pending"
+	// Approved ...
+	Approved = "approved"
+	// Sending ...
+	Sending = "sending"
 	// Sent ...
 	Sent = "sent"
 	// FailedApprove ...
@@ -40,20 +48,63 @@ type Message struct {
 	// approval of the message.
 	Content string `json:"content,string"`
 
+	// ContentType is the type of the Content, see the content package. It
+	// is empty for the opaque content.
+	ContentType string `json:"content_type,omitempty"`
+
+	// Template and TemplateVersion rendered the Content, if any, with the
+	// variant of Locale.
+	Template        string `json:"template,omitempty"`
+	TemplateVersion int32  `json:"template_version,omitempty"`
+	Locale          string `json:"locale,omitempty"`
+
+	// Render is set while the Content holds the variables of the Template,
+	// as a JSON object, until the approval renders it. TemplateVersion and
+	// Locale are the requested ones meanwhile.
+	Render bool `json:"render,omitempty"`
+
 	// Provider identifies the Backend service used to send the message.
 	Provider string `json:"provider"`
 
 	// Status ...
 	Status string `json:"status"`
+
+	// Client is the id of the authenticated client that put the message,
+	// empty when the authentication is disabled.
+	Client string `json:"client,omitempty"`
+
+	// Tenant owns the message, empty for the default tenant.
+	Tenant string `json:"tenant,omitempty"`
+
+	// TraceContext carries the trace context of the Put that created the
+	// message.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+
+	// Revision of the content, 1 when the message is put and increased by
+	// every update. It is the version expected by Update and Cancel, the
+	// messages stored before the revisions are at the first one.
+	Revision int32 `json:"revision,omitempty"`
 }
 
 // ToProto ...
 func (m *Message) ToProto() *proto.Message {
 	return &proto.Message{
 		Id:       m.ID.String(),
+		Channel:  m.Channel,
 		Content:  m.Content,
 		Provider: m.Provider,
 		Status:   m.Status,
+		Client:   m.Client,
+		Tenant:   m.Tenant,
+
+		TraceContext: m.TraceContext,
+		Revision:     m.Revision,
+		ContentType:  m.ContentType,
+
+		Template:        m.Template,
+		TemplateVersion: m.TemplateVersion,
+		Locale:          m.Locale,
+		Render:          m.Render,
 	}
 }
 
@@ -65,9 +116,19 @@ func (m *Message) FromProto(mm *proto.Message) (*Message, error) {
 	}
 
 	m.ID = id
+	m.Channel = mm.Channel
 	m.Content = mm.Content
 	m.Provider = mm.Provider
 	m.Status = mm.Status
+	m.Client = mm.Client
+	m.Tenant = mm.Tenant
+	m.TraceContext = mm.TraceContext
+	m.Revision = mm.Revision
+	m.ContentType = mm.ContentType
+	m.Template = mm.Template
+	m.TemplateVersion = mm.TemplateVersion
+	m.Locale = mm.Locale
+	m.Render = mm.Render
 
 	return m, nil
 }
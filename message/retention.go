@@ -0,0 +1,83 @@
+package message
+
+import (
+	"time"
+)
+
+// Retention actions ...
+const (
+	// RetentionDelete removes the message and its history.
+	RetentionDelete = "delete"
+	// RetentionArchive moves the message and its history to the archive.
+	RetentionArchive = "archive"
+	// RetentionStrip removes the content of the message, keeping its
+	// metadata and history.
+	RetentionStrip = "strip"
+)
+
+// RetentionRule describes how long messages are kept.
+type RetentionRule struct {
+	// Status matches the status of the message, empty matches any status.
+	Status string `yaml:"status" toml:"status" json:"status"`
+
+	// Channel matches the channel of the message, empty matches any
+	// channel.
+	Channel string `yaml:"channel" toml:"channel" json:"channel"`
+
+	// MaxAge is how long the message is kept since its last transition.
+	MaxAge time.Duration `yaml:"max_age" toml:"max_age" json:"max_age"`
+
+	// Action is one of RetentionDelete, RetentionArchive or RetentionStrip.
+	Action string `yaml:"action" toml:"action" json:"action"`
+}
+
+// Matches reports whether the rule applies to m.
+func (r *RetentionRule) Matches(m *Message) bool {
+	if r.Status != "" && r.Status != m.Status {
+		return false
+	}
+	if r.Channel != "" && r.Channel != m.Channel {
+		return false
+	}
+
+	return true
+}
+
+// RetentionPolicy is a list of rules evaluated periodically over the stored
+// messages.
+type RetentionPolicy struct {
+	// Rules are evaluated in order, the first rule that matches a message
+	// decides whether it is expired.
+	Rules []RetentionRule `yaml:"rules" toml:"rules" json:"rules"`
+
+	// Interval is the time between two sweeps.
+	Interval time.Duration `yaml:"interval" toml:"interval" json:"interval"`
+}
+
+// Rule returns the rule to apply on m, which had its last transition at
+// updatedAt, or nil if m must be kept as it is. The first rule matching m
+// decides, so the messages it keeps are not expired by the next ones.
+//
+// Active messages are never expired.
+func (p *RetentionPolicy) Rule(m *Message, updatedAt, now time.Time) *RetentionRule {
+	if IsActive(m.Status) {
+		return nil
+	}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !r.Matches(m) {
+			continue
+		}
+		// a stripped message can still expire by the next rules.
+		if r.Action == RetentionStrip && m.Content == "" {
+			continue
+		}
+		if now.Sub(updatedAt) < r.MaxAge {
+			return nil
+		}
+		return r
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package message
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionRule(t *testing.T) {
+	const day = 24 * time.Hour
+	p := &RetentionPolicy{Rules: []RetentionRule{
+		{Status: Sent, MaxAge: 30 * day, Action: RetentionDelete},
+		{Channel: "sms", Status: FailedDeliver, MaxAge: day, Action: RetentionStrip},
+		{MaxAge: 7 * day, Action: RetentionArchive},
+	}}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		m    Message
+		age  time.Duration
+		want int
+	}{
+		{"sent kept by its own rule", Message{Status: Sent, Content: "c"}, 10 * day, -1},
+		{"sent expired", Message{Status: Sent, Content: "c"}, 31 * day, 0},
+		{"cancelled kept", Message{Status: Cancelled, Content: "c"}, 6 * day, -1},
+		{"cancelled expired", Message{Status: Cancelled, Content: "c"}, 8 * day, 2},
+		{"failed sms stripped", Message{Channel: "sms", Status: FailedDeliver, Content: "c"}, 2 * day, 1},
+		{"stripped sms kept", Message{Channel: "sms", Status: FailedDeliver}, 2 * day, -1},
+		{"stripped sms archived", Message{Channel: "sms", Status: FailedDeliver}, 8 * day, 2},
+		{"failed email kept", Message{Channel: "email", Status: FailedDeliver, Content: "c"}, 2 * day, -1},
+		{"active never expired", Message{Status: Approved, Content: "c"}, 100 * day, -1},
+	}
+
+	for _, tt := range tests {
+		got := p.Rule(&tt.m, now.Add(-tt.age), now)
+		var want *RetentionRule
+		if tt.want >= 0 {
+			want = &p.Rules[tt.want]
+		}
+		if got != want {
+			t.Errorf("%s: Rule() = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package message
+
+import (
+	"fmt"
+)
+
+// transitions lists the statuses a message can move to from each status.
+//
+// A message is created as Received, approved before being queued and moved
+// to Sending right before the delivery, so a message can only be cancelled
+// or updated while it is waiting for its approval or in the queue. The
+// legacy Pending messages were approved before being stored.
+var transitions = map[string][]string{
+	"":            {Received},
+	Received:      {Approved, FailedApprove, CrashedApprove, Cancelled},
+	Pending:       {Sending, Cancelled},
+	Approved:      {Sending, Cancelled},
+	Sending:       {Sent, FailedDeliver, CrashedDeliver},
+	FailedDeliver: {Sending},
+}
+
+// editable lists the statuses where the content of a message can change.
+var editable = map[string]bool{
+	Received: true,
+	Pending:  true,
+	Approved: true,
+}
+
+// CanTransition reports whether a message can move from status to next.
+func CanTransition(status, next string) bool {
+	for _, s := range transitions[status] {
+		if s == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanUpdate reports whether the content of a message can be updated while
+// it has the given status.
+func CanUpdate(status string) bool {
+	return editable[status]
+}
+
+// StatusError is returned when an action is not allowed by the current
+// status of a message.
+type StatusError struct {
+	// Status is the current status of the message.
+	Status string
+
+	// Action describes the rejected action.
+	Action string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("cannot %s a message with status %q", e.Action, e.Status)
+}
+
+// CheckTransition returns a *StatusError if a message cannot move from
+// status to next.
+func CheckTransition(status, next string) error {
+	if !CanTransition(status, next) {
+		return &StatusError{Status: status, Action: "mark as " + next}
+	}
+
+	return nil
+}
+
+// IsReceived reports whether a message with the given status is waiting
+// for its approval.
+func IsReceived(status string) bool {
+	return status == Received
+}
+
+// IsApproved reports whether a message with the given status is approved
+// and waiting in the queue.
+func IsApproved(status string) bool {
+	return status == Approved || status == Pending
+}
+
+// CheckUpdate returns a *StatusError if the content of a message with the
+// given status cannot be updated.
+func CheckUpdate(status string) error {
+	if !CanUpdate(status) {
+		return &StatusError{Status: status, Action: "update"}
+	}
+
+	return nil
+}
+
+// IsActive reports whether a message with the given status is still
+// waiting for its approval, in the queue or being delivered.
+func IsActive(status string) bool {
+	switch status {
+	case Received, Pending, Approved, Sending:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		status, next string
+		ok           bool
+	}{
+		{"", Received, true},
+		{"", Approved, false},
+		{Received, Approved, true},
+		{Received, FailedApprove, true},
+		{Received, CrashedApprove, true},
+		{Received, Cancelled, true},
+		{Received, Sending, false},
+		{Pending, Sending, true},
+		{Pending, Cancelled, true},
+		{Pending, Approved, false},
+		{Approved, Sending, true},
+		{Approved, Cancelled, true},
+		{Approved, Sent, false},
+		{Sending, Sent, true},
+		{Sending, FailedDeliver, true},
+		{Sending, CrashedDeliver, true},
+		{Sending, Cancelled, false},
+		{FailedDeliver, Sending, true},
+		{FailedDeliver, Cancelled, false},
+		{Sent, Sending, false},
+		{Cancelled, Approved, false},
+		{CrashedApprove, Approved, false},
+	}
+
+	for _, tt := range tests {
+		if ok := CanTransition(tt.status, tt.next); ok != tt.ok {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.status, tt.next, ok, tt.ok)
+		}
+
+		err := CheckTransition(tt.status, tt.next)
+		var se *StatusError
+		switch {
+		case tt.ok && err != nil:
+			t.Errorf("CheckTransition(%q, %q) = %v, want nil", tt.status, tt.next, err)
+		case !tt.ok && (!errors.As(err, &se) || se.Status != tt.status):
+			t.Errorf("CheckTransition(%q, %q) = %v, want a *StatusError", tt.status, tt.next, err)
+		}
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	tests := []struct {
+		status                     string
+		update, received, approved bool
+		active                     bool
+	}{
+		{Received, true, true, false, true},
+		{Pending, true, false, true, true},
+		{Approved, true, false, true, true},
+		{Sending, false, false, false, true},
+		{Sent, false, false, false, false},
+		{FailedApprove, false, false, false, false},
+		{CrashedApprove, false, false, false, false},
+		{FailedDeliver, false, false, false, false},
+		{CrashedDeliver, false, false, false, false},
+		{Cancelled, false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		if err := CheckUpdate(tt.status); (err == nil) != tt.update {
+			t.Errorf("CheckUpdate(%q) = %v, want ok %v", tt.status, err, tt.update)
+		}
+		if got := IsReceived(tt.status); got != tt.received {
+			t.Errorf("IsReceived(%q) = %v, want %v", tt.status, got, tt.received)
+		}
+		if got := IsApproved(tt.status); got != tt.approved {
+			t.Errorf("IsApproved(%q) = %v, want %v", tt.status, got, tt.approved)
+		}
+		if got := IsActive(tt.status); got != tt.active {
+			t.Errorf("IsActive(%q) = %v, want %v", tt.status, got, tt.active)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package message
+
+import (
+	"time"
+
+	"github.com/microapis/messages-core/proto"
+)
+
+// Sources ...
+const (
+	// SourceAPI identifies transitions requested through the gRPC service.
+	SourceAPI = "api"
+	// SourceScheduler identifies transitions made by the scheduler loop.
+	SourceScheduler = "scheduler"
+)
+
+// Transition describes a change of status of a message.
+//
+// Transitions are stored in an append-only history per message, so they
+// are never updated once recorded.
+type Transition struct {
+	// From is the status before the transition, empty when the message
+	// was created.
+	From string `json:"from"`
+
+	// To is the status after the transition.
+	To string `json:"to"`
+
+	// Time is when the transition was recorded.
+	Time time.Time `json:"time"`
+
+	// Source identifies who requested the transition.
+	Source string `json:"source"`
+
+	// Error describes why the transition happened, if it was caused by
+	// a failure.
+	Error string `json:"error,omitempty"`
+
+	// Provider is the provider used when the transition happened.
+	Provider string `json:"provider,omitempty"`
+
+	// Attempt is the number of the delivery attempt, if any.
+	Attempt int32 `json:"attempt,omitempty"`
+
+	// Client is the id of the authenticated client that requested the
+	// transition, empty for the ones of the scheduler or when the
+	// authentication is disabled.
+	Client string `json:"client,omitempty"`
+}
+
+// ToProto ...
+func (t *Transition) ToProto() *proto.StatusTransition {
+	return &proto.StatusTransition{
+		From:     t.From,
+		To:       t.To,
+		Time:     t.Time.UnixNano() / int64(time.Millisecond),
+		Source:   t.Source,
+		Error:    t.Error,
+		Provider: t.Provider,
+		Attempt:  t.Attempt,
+		Client:   t.Client,
+	}
+}
+
+// FromProto ...
+func (t *Transition) FromProto(tt *proto.StatusTransition) *Transition {
+	t.From = tt.From
+	t.To = tt.To
+	t.Time = time.Unix(0, tt.Time*int64(time.Millisecond))
+	t.Source = tt.Source
+	t.Error = tt.Error
+	t.Provider = tt.Provider
+	t.Attempt = tt.Attempt
+	t.Client = tt.Client
+
+	return t
+}
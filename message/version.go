@@ -0,0 +1,43 @@
+package message
+
+import (
+	"time"
+
+	"github.com/microapis/messages-core/proto"
+)
+
+// Version is a former content of a message, kept when an update replaces
+// it so the changes can be audited.
+type Version struct {
+	// Revision of the content.
+	Revision int32 `json:"revision"`
+
+	// Content is the content of the revision.
+	Content string `json:"content"`
+
+	// Time is when the content was replaced.
+	Time time.Time `json:"time"`
+
+	// Client is the id of the authenticated client that replaced it.
+	Client string `json:"client,omitempty"`
+}
+
+// ToProto ...
+func (v *Version) ToProto() *proto.ContentVersion {
+	return &proto.ContentVersion{
+		Revision: v.Revision,
+		Content:  v.Content,
+		Time:     v.Time.UnixNano() / int64(time.Millisecond),
+		Client:   v.Client,
+	}
+}
+
+// FromProto ...
+func (v *Version) FromProto(cv *proto.ContentVersion) *Version {
+	v.Revision = cv.Revision
+	v.Content = cv.Content
+	v.Time = time.Unix(0, cv.Time*int64(time.Millisecond))
+	v.Client = cv.Client
+
+	return v
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "messages"
+
+// Unknown is the label value of the channels and providers that are not
+// registered, so the names sent by the clients do not create new series.
+const Unknown = "unknown"
+
+// Registry keeps the metrics of the service, with the go runtime and
+// process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// Transitions counts the status transitions of the messages.
+	Transitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transitions_total",
+		Help:      "Status transitions of the messages.",
+	}, []string{"tenant", "channel", "provider", "status"})
+
+	// Lateness observes the seconds between the time a message was
+	// scheduled to, encoded in its id, and the time it was sent.
+	Lateness = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "delivery_lateness_seconds",
+		Help:      "Seconds between the scheduled and the actual send time of the messages.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900, 3600},
+	}, []string{"tenant", "channel"})
+
+	// BackendDuration observes the latency of the calls to the backends.
+	BackendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "backend_request_duration_seconds",
+		Help:      "Latency of the calls to the channel backends.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"channel", "method", "result"})
+
+	// InFlight is the number of deliveries in progress.
+	InFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "deliveries_in_flight",
+		Help:      "Deliveries in progress.",
+	}, []string{"tenant"})
+
+	queues = &queueCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "size"),
+			"Messages waiting on the priority queue.",
+			[]string{"tenant"}, nil,
+		),
+		queues: make(map[string]Lener),
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		Transitions,
+		Lateness,
+		BackendDuration,
+		InFlight,
+		queues,
+		grpcStarted,
+		grpcHandled,
+		grpcDuration,
+	)
+}
+
+// Handler serves the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ListenAndServe serves the metrics on addr at /metrics.
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	return http.ListenAndServe(addr, mux)
+}
+
+// Result returns the result label of a call failed with err.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "ok"
+}
+
+// ObserveBackend observes a call to the backend of channel started at
+// start.
+func ObserveBackend(channel, method string, start time.Time, err error) {
+	BackendDuration.WithLabelValues(channel, method, Result(err)).Observe(time.Since(start).Seconds())
+}
+
+// Lener is implemented by the priority queues.
+type Lener interface {
+	Len() (int, error)
+}
+
+// RegisterQueue reports the size of the queue of tenant on every scrape.
+func RegisterQueue(tenant string, q Lener) {
+	queues.mu.Lock()
+	defer queues.mu.Unlock()
+
+	queues.queues[tenant] = q
+}
+
+// queueCollector reads the size of the queues when collected, so it does
+// not drift from the queue shared by several instances.
+type queueCollector struct {
+	desc *prometheus.Desc
+
+	mu     sync.Mutex
+	queues map[string]Lener
+}
+
+// Describe ...
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect ...
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for tenant, q := range c.queues {
+		n, err := q.Len()
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.desc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), tenant)
+	}
+}
@@ -1,753 +1,3552 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
 // source: proto/messages.proto
 
 package proto
 
 import (
 	context "context"
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
-	math "math"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 type MessagesError struct {
-	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *MessagesError) Reset()         { *m = MessagesError{} }
-func (m *MessagesError) String() string { return proto.CompactTextString(m) }
-func (*MessagesError) ProtoMessage()    {}
-func (*MessagesError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{0}
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (m *MessagesError) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessagesError.Unmarshal(m, b)
-}
-func (m *MessagesError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessagesError.Marshal(b, m, deterministic)
-}
-func (m *MessagesError) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessagesError.Merge(m, src)
+func (x *MessagesError) Reset() {
+	*x = MessagesError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessagesError) XXX_Size() int {
-	return xxx_messageInfo_MessagesError.Size(m)
+
+func (x *MessagesError) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *MessagesError) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessagesError.DiscardUnknown(m)
+
+func (*MessagesError) ProtoMessage() {}
+
+func (x *MessagesError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_MessagesError proto.InternalMessageInfo
+// Deprecated: Use MessagesError.ProtoReflect.Descriptor instead.
+func (*MessagesError) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{0}
+}
 
-func (m *MessagesError) GetCode() int32 {
-	if m != nil {
-		return m.Code
+func (x *MessagesError) GetCode() int32 {
+	if x != nil {
+		return x.Code
 	}
 	return 0
 }
 
-func (m *MessagesError) GetMessage() string {
-	if m != nil {
-		return m.Message
+func (x *MessagesError) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
 type Message struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Channel              string   `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
-	Provider             string   `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
-	Content              string   `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	Status               string   `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Message) Reset()         { *m = Message{} }
-func (m *Message) String() string { return proto.CompactTextString(m) }
-func (*Message) ProtoMessage()    {}
-func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{1}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Channel  string              `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	Provider string              `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Content  string              `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Status   string              `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	History  []*StatusTransition `protobuf:"bytes,6,rep,name=history,proto3" json:"history,omitempty"`
+	// client is the id of the authenticated client that put the message.
+	Client string `protobuf:"bytes,7,opt,name=client,proto3" json:"client,omitempty"`
+	// tenant owns the message, empty for the default tenant.
+	Tenant string `protobuf:"bytes,8,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// encrypted_content replaces content at rest when the encryption is
+	// enabled, it is never sent to the clients.
+	EncryptedContent *Envelope `protobuf:"bytes,9,opt,name=encrypted_content,json=encryptedContent,proto3" json:"encrypted_content,omitempty"`
+	// trace_context is the W3C trace context of the Put that created the
+	// message, so its delivery can be linked to it.
+	TraceContext map[string]string `protobuf:"bytes,10,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// revision of the content, 1 when put and increased by every update.
+	// It is the version expected by Update and Cancel.
+	Revision int32 `protobuf:"varint,11,opt,name=revision,proto3" json:"revision,omitempty"`
+	// versions are the former contents, replaced by the updates.
+	Versions []*ContentVersion `protobuf:"bytes,12,rep,name=versions,proto3" json:"versions,omitempty"`
+	// content_type is text/plain (default), application/json or
+	// application/protobuf, whose content is a base64 encoded
+	// google.protobuf.Any.
+	ContentType string `protobuf:"bytes,13,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// template and template_version rendered the content, if any.
+	Template        string `protobuf:"bytes,14,opt,name=template,proto3" json:"template,omitempty"`
+	TemplateVersion int32  `protobuf:"varint,15,opt,name=template_version,json=templateVersion,proto3" json:"template_version,omitempty"`
+	// locale of the variant of the template that rendered the content.
+	Locale string `protobuf:"bytes,16,opt,name=locale,proto3" json:"locale,omitempty"`
+	// render is set while the content holds the variables of the template,
+	// as a JSON object, until the approval renders it. template_version and
+	// locale are the requested ones meanwhile.
+	Render bool `protobuf:"varint,17,opt,name=render,proto3" json:"render,omitempty"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *Message) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Message.Unmarshal(m, b)
-}
-func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
-}
-func (m *Message) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Message.Merge(m, src)
-}
-func (m *Message) XXX_Size() int {
-	return xxx_messageInfo_Message.Size(m)
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Message) XXX_DiscardUnknown() {
-	xxx_messageInfo_Message.DiscardUnknown(m)
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_Message proto.InternalMessageInfo
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *Message) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *Message) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-func (m *Message) GetChannel() string {
-	if m != nil {
-		return m.Channel
+func (x *Message) GetChannel() string {
+	if x != nil {
+		return x.Channel
 	}
 	return ""
 }
 
-func (m *Message) GetProvider() string {
-	if m != nil {
-		return m.Provider
+func (x *Message) GetProvider() string {
+	if x != nil {
+		return x.Provider
 	}
 	return ""
 }
 
-func (m *Message) GetContent() string {
-	if m != nil {
-		return m.Content
+func (x *Message) GetContent() string {
+	if x != nil {
+		return x.Content
 	}
 	return ""
 }
 
-func (m *Message) GetStatus() string {
-	if m != nil {
-		return m.Status
+func (x *Message) GetStatus() string {
+	if x != nil {
+		return x.Status
 	}
 	return ""
 }
 
-type Channel struct {
-	Name                 string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Providers            []*Provider `protobuf:"bytes,2,rep,name=providers,proto3" json:"providers,omitempty"`
-	Host                 string      `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
-	Port                 string      `protobuf:"bytes,4,opt,name=port,proto3" json:"port,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
-}
-
-func (m *Channel) Reset()         { *m = Channel{} }
-func (m *Channel) String() string { return proto.CompactTextString(m) }
-func (*Channel) ProtoMessage()    {}
-func (*Channel) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{2}
+func (x *Message) GetHistory() []*StatusTransition {
+	if x != nil {
+		return x.History
+	}
+	return nil
 }
 
-func (m *Channel) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Channel.Unmarshal(m, b)
-}
-func (m *Channel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Channel.Marshal(b, m, deterministic)
-}
-func (m *Channel) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Channel.Merge(m, src)
+func (x *Message) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
 }
-func (m *Channel) XXX_Size() int {
-	return xxx_messageInfo_Channel.Size(m)
+
+func (x *Message) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
 }
-func (m *Channel) XXX_DiscardUnknown() {
-	xxx_messageInfo_Channel.DiscardUnknown(m)
+
+func (x *Message) GetEncryptedContent() *Envelope {
+	if x != nil {
+		return x.EncryptedContent
+	}
+	return nil
 }
 
-var xxx_messageInfo_Channel proto.InternalMessageInfo
+func (x *Message) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
 
-func (m *Channel) GetName() string {
-	if m != nil {
-		return m.Name
+func (x *Message) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
 	}
-	return ""
+	return 0
 }
 
-func (m *Channel) GetProviders() []*Provider {
-	if m != nil {
-		return m.Providers
+func (x *Message) GetVersions() []*ContentVersion {
+	if x != nil {
+		return x.Versions
 	}
 	return nil
 }
 
-func (m *Channel) GetHost() string {
-	if m != nil {
-		return m.Host
+func (x *Message) GetContentType() string {
+	if x != nil {
+		return x.ContentType
 	}
 	return ""
 }
 
-func (m *Channel) GetPort() string {
-	if m != nil {
-		return m.Port
+func (x *Message) GetTemplate() string {
+	if x != nil {
+		return x.Template
 	}
 	return ""
 }
 
-type Provider struct {
-	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Params               map[string]string `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (x *Message) GetTemplateVersion() int32 {
+	if x != nil {
+		return x.TemplateVersion
+	}
+	return 0
 }
 
-func (m *Provider) Reset()         { *m = Provider{} }
-func (m *Provider) String() string { return proto.CompactTextString(m) }
-func (*Provider) ProtoMessage()    {}
-func (*Provider) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{3}
+func (x *Message) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
 }
 
-func (m *Provider) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Provider.Unmarshal(m, b)
-}
-func (m *Provider) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Provider.Marshal(b, m, deterministic)
+func (x *Message) GetRender() bool {
+	if x != nil {
+		return x.Render
+	}
+	return false
+}
+
+// ContentVersion is a former content of a message.
+type ContentVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revision int32  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Content  string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// time is the unix time in milliseconds when it was replaced.
+	Time int64 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	// encrypted_content replaces content at rest when the encryption is
+	// enabled, it is never sent to the clients.
+	EncryptedContent *Envelope `protobuf:"bytes,4,opt,name=encrypted_content,json=encryptedContent,proto3" json:"encrypted_content,omitempty"`
+	// client is the id of the authenticated client that replaced it.
+	Client string `protobuf:"bytes,5,opt,name=client,proto3" json:"client,omitempty"`
+}
+
+func (x *ContentVersion) Reset() {
+	*x = ContentVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *Provider) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Provider.Merge(m, src)
+
+func (x *ContentVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Provider) XXX_Size() int {
-	return xxx_messageInfo_Provider.Size(m)
+
+func (*ContentVersion) ProtoMessage() {}
+
+func (x *ContentVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *Provider) XXX_DiscardUnknown() {
-	xxx_messageInfo_Provider.DiscardUnknown(m)
+
+// Deprecated: Use ContentVersion.ProtoReflect.Descriptor instead.
+func (*ContentVersion) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{2}
 }
 
-var xxx_messageInfo_Provider proto.InternalMessageInfo
+func (x *ContentVersion) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
 
-func (m *Provider) GetName() string {
-	if m != nil {
-		return m.Name
+func (x *ContentVersion) GetContent() string {
+	if x != nil {
+		return x.Content
 	}
 	return ""
 }
 
-func (m *Provider) GetParams() map[string]string {
-	if m != nil {
-		return m.Params
+func (x *ContentVersion) GetTime() int64 {
+	if x != nil {
+		return x.Time
 	}
-	return nil
+	return 0
 }
 
-type MessagePutRequest struct {
-	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Provider             string   `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
-	Content              string   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	Delay                int64    `protobuf:"varint,4,opt,name=delay,proto3" json:"delay,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *MessagePutRequest) Reset()         { *m = MessagePutRequest{} }
-func (m *MessagePutRequest) String() string { return proto.CompactTextString(m) }
-func (*MessagePutRequest) ProtoMessage()    {}
-func (*MessagePutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{4}
+func (x *ContentVersion) GetEncryptedContent() *Envelope {
+	if x != nil {
+		return x.EncryptedContent
+	}
+	return nil
 }
 
-func (m *MessagePutRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessagePutRequest.Unmarshal(m, b)
-}
-func (m *MessagePutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessagePutRequest.Marshal(b, m, deterministic)
+func (x *ContentVersion) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
 }
-func (m *MessagePutRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessagePutRequest.Merge(m, src)
+
+type Envelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Key   []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Nonce []byte `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Data  []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
 }
-func (m *MessagePutRequest) XXX_Size() int {
-	return xxx_messageInfo_MessagePutRequest.Size(m)
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessagePutRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessagePutRequest.DiscardUnknown(m)
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_MessagePutRequest proto.InternalMessageInfo
+func (*Envelope) ProtoMessage() {}
 
-func (m *MessagePutRequest) GetChannel() string {
-	if m != nil {
-		return m.Channel
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (m *MessagePutRequest) GetProvider() string {
-	if m != nil {
-		return m.Provider
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Envelope) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
 	}
 	return ""
 }
 
-func (m *MessagePutRequest) GetContent() string {
-	if m != nil {
-		return m.Content
+func (x *Envelope) GetKey() []byte {
+	if x != nil {
+		return x.Key
 	}
-	return ""
+	return nil
 }
 
-func (m *MessagePutRequest) GetDelay() int64 {
-	if m != nil {
-		return m.Delay
+func (x *Envelope) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
 	}
-	return 0
+	return nil
 }
 
-type MessagePutDataResponse struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *Envelope) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
 }
 
-func (m *MessagePutDataResponse) Reset()         { *m = MessagePutDataResponse{} }
-func (m *MessagePutDataResponse) String() string { return proto.CompactTextString(m) }
-func (*MessagePutDataResponse) ProtoMessage()    {}
-func (*MessagePutDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{5}
+type StatusTransition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// time is the unix time in milliseconds.
+	Time     int64  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Source   string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Error    string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	Attempt  int32  `protobuf:"varint,7,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// client is the id of the authenticated client that requested the
+	// transition, empty for the ones of the scheduler.
+	Client string `protobuf:"bytes,8,opt,name=client,proto3" json:"client,omitempty"`
+}
+
+func (x *StatusTransition) Reset() {
+	*x = StatusTransition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *MessagePutDataResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessagePutDataResponse.Unmarshal(m, b)
+func (x *StatusTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *MessagePutDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessagePutDataResponse.Marshal(b, m, deterministic)
+
+func (*StatusTransition) ProtoMessage() {}
+
+func (x *StatusTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *MessagePutDataResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessagePutDataResponse.Merge(m, src)
+
+// Deprecated: Use StatusTransition.ProtoReflect.Descriptor instead.
+func (*StatusTransition) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{4}
 }
-func (m *MessagePutDataResponse) XXX_Size() int {
-	return xxx_messageInfo_MessagePutDataResponse.Size(m)
+
+func (x *StatusTransition) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
 }
-func (m *MessagePutDataResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessagePutDataResponse.DiscardUnknown(m)
+
+func (x *StatusTransition) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
 }
 
-var xxx_messageInfo_MessagePutDataResponse proto.InternalMessageInfo
+func (x *StatusTransition) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
 
-func (m *MessagePutDataResponse) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *StatusTransition) GetSource() string {
+	if x != nil {
+		return x.Source
 	}
 	return ""
 }
 
-type MessagePutResponse struct {
-	Data                 *MessagePutDataResponse `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Error                *MessagesError          `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
-	XXX_unrecognized     []byte                  `json:"-"`
-	XXX_sizecache        int32                   `json:"-"`
+func (x *StatusTransition) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
 }
 
-func (m *MessagePutResponse) Reset()         { *m = MessagePutResponse{} }
-func (m *MessagePutResponse) String() string { return proto.CompactTextString(m) }
-func (*MessagePutResponse) ProtoMessage()    {}
-func (*MessagePutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{6}
+func (x *StatusTransition) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
 }
 
-func (m *MessagePutResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessagePutResponse.Unmarshal(m, b)
+func (x *StatusTransition) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
 }
-func (m *MessagePutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessagePutResponse.Marshal(b, m, deterministic)
+
+func (x *StatusTransition) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
 }
-func (m *MessagePutResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessagePutResponse.Merge(m, src)
+
+type Channel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Providers []*Provider `protobuf:"bytes,2,rep,name=providers,proto3" json:"providers,omitempty"`
+	Host      string      `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Port      string      `protobuf:"bytes,4,opt,name=port,proto3" json:"port,omitempty"`
+	// schema of the content of the messages, if any.
+	Schema *ContentSchema `protobuf:"bytes,5,opt,name=schema,proto3" json:"schema,omitempty"`
+}
+
+func (x *Channel) Reset() {
+	*x = Channel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessagePutResponse) XXX_Size() int {
-	return xxx_messageInfo_MessagePutResponse.Size(m)
+
+func (x *Channel) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *MessagePutResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessagePutResponse.DiscardUnknown(m)
+
+func (*Channel) ProtoMessage() {}
+
+func (x *Channel) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_MessagePutResponse proto.InternalMessageInfo
+// Deprecated: Use Channel.ProtoReflect.Descriptor instead.
+func (*Channel) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{5}
+}
 
-func (m *MessagePutResponse) GetData() *MessagePutDataResponse {
-	if m != nil {
-		return m.Data
+func (x *Channel) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *MessagePutResponse) GetError() *MessagesError {
-	if m != nil {
-		return m.Error
+func (x *Channel) GetProviders() []*Provider {
+	if x != nil {
+		return x.Providers
 	}
 	return nil
 }
 
-type MessageGetRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *Channel) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
 }
 
-func (m *MessageGetRequest) Reset()         { *m = MessageGetRequest{} }
-func (m *MessageGetRequest) String() string { return proto.CompactTextString(m) }
-func (*MessageGetRequest) ProtoMessage()    {}
-func (*MessageGetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{7}
+func (x *Channel) GetPort() string {
+	if x != nil {
+		return x.Port
+	}
+	return ""
 }
 
-func (m *MessageGetRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageGetRequest.Unmarshal(m, b)
-}
-func (m *MessageGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageGetRequest.Marshal(b, m, deterministic)
+func (x *Channel) GetSchema() *ContentSchema {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
 }
-func (m *MessageGetRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageGetRequest.Merge(m, src)
+
+// ContentSchema is either a JSON Schema or the protobuf message of the
+// content.
+type ContentSchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Json []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	// descriptor is a serialized google.protobuf.FileDescriptorSet with
+	// the message and its dependencies.
+	Descriptor_ []byte `protobuf:"bytes,2,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	Message     string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 }
-func (m *MessageGetRequest) XXX_Size() int {
-	return xxx_messageInfo_MessageGetRequest.Size(m)
+
+func (x *ContentSchema) Reset() {
+	*x = ContentSchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessageGetRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageGetRequest.DiscardUnknown(m)
+
+func (x *ContentSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_MessageGetRequest proto.InternalMessageInfo
+func (*ContentSchema) ProtoMessage() {}
 
-func (m *MessageGetRequest) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *ContentSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-type MessageGetResponse struct {
-	Data                 *Message       `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Error                *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+// Deprecated: Use ContentSchema.ProtoReflect.Descriptor instead.
+func (*ContentSchema) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{6}
 }
 
-func (m *MessageGetResponse) Reset()         { *m = MessageGetResponse{} }
-func (m *MessageGetResponse) String() string { return proto.CompactTextString(m) }
-func (*MessageGetResponse) ProtoMessage()    {}
-func (*MessageGetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{8}
+func (x *ContentSchema) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+func (x *ContentSchema) GetDescriptor_() []byte {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
 }
 
-func (m *MessageGetResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageGetResponse.Unmarshal(m, b)
+func (x *ContentSchema) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
-func (m *MessageGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageGetResponse.Marshal(b, m, deterministic)
+
+type Provider struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Params map[string]string `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
-func (m *MessageGetResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageGetResponse.Merge(m, src)
+
+func (x *Provider) Reset() {
+	*x = Provider{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessageGetResponse) XXX_Size() int {
-	return xxx_messageInfo_MessageGetResponse.Size(m)
+
+func (x *Provider) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *MessageGetResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageGetResponse.DiscardUnknown(m)
+
+func (*Provider) ProtoMessage() {}
+
+func (x *Provider) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_MessageGetResponse proto.InternalMessageInfo
+// Deprecated: Use Provider.ProtoReflect.Descriptor instead.
+func (*Provider) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{7}
+}
 
-func (m *MessageGetResponse) GetData() *Message {
-	if m != nil {
-		return m.Data
+func (x *Provider) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *MessageGetResponse) GetError() *MessagesError {
-	if m != nil {
-		return m.Error
+func (x *Provider) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
 	}
 	return nil
 }
 
-type MessageUpdateRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Content              string   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type MessagePutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel  string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Provider string `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Content  string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Delay    int64  `protobuf:"varint,4,opt,name=delay,proto3" json:"delay,omitempty"`
+	// content_type of content, text/plain by default.
+	ContentType string `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// any_content replaces content with a typed one, of type
+	// application/protobuf.
+	AnyContent *anypb.Any `protobuf:"bytes,6,opt,name=any_content,json=anyContent,proto3" json:"any_content,omitempty"`
+	// template of the channel renders the content with the variables,
+	// template_version 0 is the latest one.
+	Template        string           `protobuf:"bytes,7,opt,name=template,proto3" json:"template,omitempty"`
+	TemplateVersion int32            `protobuf:"varint,8,opt,name=template_version,json=templateVersion,proto3" json:"template_version,omitempty"`
+	Variables       *structpb.Struct `protobuf:"bytes,9,opt,name=variables,proto3" json:"variables,omitempty"`
+	// locale of the recipient selects the variant of the template, falling
+	// back to its parents and to the default locale.
+	Locale string `protobuf:"bytes,10,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *MessagePutRequest) Reset() {
+	*x = MessagePutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *MessageUpdateRequest) Reset()         { *m = MessageUpdateRequest{} }
-func (m *MessageUpdateRequest) String() string { return proto.CompactTextString(m) }
-func (*MessageUpdateRequest) ProtoMessage()    {}
-func (*MessageUpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{9}
+func (x *MessagePutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *MessageUpdateRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageUpdateRequest.Unmarshal(m, b)
+func (*MessagePutRequest) ProtoMessage() {}
+
+func (x *MessagePutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *MessageUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageUpdateRequest.Marshal(b, m, deterministic)
+
+// Deprecated: Use MessagePutRequest.ProtoReflect.Descriptor instead.
+func (*MessagePutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{8}
 }
-func (m *MessageUpdateRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageUpdateRequest.Merge(m, src)
+
+func (x *MessagePutRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
 }
-func (m *MessageUpdateRequest) XXX_Size() int {
-	return xxx_messageInfo_MessageUpdateRequest.Size(m)
+
+func (x *MessagePutRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
 }
-func (m *MessageUpdateRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageUpdateRequest.DiscardUnknown(m)
+
+func (x *MessagePutRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
 }
 
-var xxx_messageInfo_MessageUpdateRequest proto.InternalMessageInfo
+func (x *MessagePutRequest) GetDelay() int64 {
+	if x != nil {
+		return x.Delay
+	}
+	return 0
+}
 
-func (m *MessageUpdateRequest) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *MessagePutRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
 	}
 	return ""
 }
 
-func (m *MessageUpdateRequest) GetContent() string {
-	if m != nil {
-		return m.Content
+func (x *MessagePutRequest) GetAnyContent() *anypb.Any {
+	if x != nil {
+		return x.AnyContent
 	}
-	return ""
+	return nil
 }
 
-type MessageUpdateResponse struct {
-	Error                *MessagesError `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+func (x *MessagePutRequest) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
 }
 
-func (m *MessageUpdateResponse) Reset()         { *m = MessageUpdateResponse{} }
-func (m *MessageUpdateResponse) String() string { return proto.CompactTextString(m) }
-func (*MessageUpdateResponse) ProtoMessage()    {}
-func (*MessageUpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{10}
+func (x *MessagePutRequest) GetTemplateVersion() int32 {
+	if x != nil {
+		return x.TemplateVersion
+	}
+	return 0
 }
 
-func (m *MessageUpdateResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageUpdateResponse.Unmarshal(m, b)
+func (x *MessagePutRequest) GetVariables() *structpb.Struct {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
 }
-func (m *MessageUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageUpdateResponse.Marshal(b, m, deterministic)
+
+func (x *MessagePutRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
 }
-func (m *MessageUpdateResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageUpdateResponse.Merge(m, src)
+
+type MessagePutDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
-func (m *MessageUpdateResponse) XXX_Size() int {
-	return xxx_messageInfo_MessageUpdateResponse.Size(m)
+
+func (x *MessagePutDataResponse) Reset() {
+	*x = MessagePutDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessageUpdateResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageUpdateResponse.DiscardUnknown(m)
+
+func (x *MessagePutDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_MessageUpdateResponse proto.InternalMessageInfo
+func (*MessagePutDataResponse) ProtoMessage() {}
 
-func (m *MessageUpdateResponse) GetError() *MessagesError {
-	if m != nil {
-		return m.Error
+func (x *MessagePutDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type MessageCancelRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+// Deprecated: Use MessagePutDataResponse.ProtoReflect.Descriptor instead.
+func (*MessagePutDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{9}
 }
 
-func (m *MessageCancelRequest) Reset()         { *m = MessageCancelRequest{} }
-func (m *MessageCancelRequest) String() string { return proto.CompactTextString(m) }
-func (*MessageCancelRequest) ProtoMessage()    {}
-func (*MessageCancelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{11}
+func (x *MessagePutDataResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (m *MessageCancelRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageCancelRequest.Unmarshal(m, b)
+type MessagePutResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *MessagePutDataResponse `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError          `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 }
-func (m *MessageCancelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageCancelRequest.Marshal(b, m, deterministic)
+
+func (x *MessagePutResponse) Reset() {
+	*x = MessagePutResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *MessageCancelRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageCancelRequest.Merge(m, src)
+
+func (x *MessagePutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *MessageCancelRequest) XXX_Size() int {
-	return xxx_messageInfo_MessageCancelRequest.Size(m)
+
+func (*MessagePutResponse) ProtoMessage() {}
+
+func (x *MessagePutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *MessageCancelRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageCancelRequest.DiscardUnknown(m)
+
+// Deprecated: Use MessagePutResponse.ProtoReflect.Descriptor instead.
+func (*MessagePutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{10}
 }
 
-var xxx_messageInfo_MessageCancelRequest proto.InternalMessageInfo
+func (x *MessagePutResponse) GetData() *MessagePutDataResponse {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
 
-func (m *MessageCancelRequest) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *MessagePutResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
 	}
-	return ""
+	return nil
 }
 
-type MessageCancelResponse struct {
-	Error                *MessagesError `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+type MessageGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WithHistory  bool   `protobuf:"varint,2,opt,name=with_history,json=withHistory,proto3" json:"with_history,omitempty"`
+	WithVersions bool   `protobuf:"varint,3,opt,name=with_versions,json=withVersions,proto3" json:"with_versions,omitempty"`
 }
 
-func (m *MessageCancelResponse) Reset()         { *m = MessageCancelResponse{} }
-func (m *MessageCancelResponse) String() string { return proto.CompactTextString(m) }
-func (*MessageCancelResponse) ProtoMessage()    {}
-func (*MessageCancelResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_346d92f49d8efbd3, []int{12}
-}
-
-func (m *MessageCancelResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MessageCancelResponse.Unmarshal(m, b)
-}
-func (m *MessageCancelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MessageCancelResponse.Marshal(b, m, deterministic)
-}
-func (m *MessageCancelResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MessageCancelResponse.Merge(m, src)
-}
-func (m *MessageCancelResponse) XXX_Size() int {
-	return xxx_messageInfo_MessageCancelResponse.Size(m)
-}
-func (m *MessageCancelResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MessageCancelResponse.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_MessageCancelResponse proto.InternalMessageInfo
-
-func (m *MessageCancelResponse) GetError() *MessagesError {
-	if m != nil {
-		return m.Error
-	}
-	return nil
-}
-
-func init() {
-	proto.RegisterType((*MessagesError)(nil), "proto.MessagesError")
-	proto.RegisterType((*Message)(nil), "proto.Message")
-	proto.RegisterType((*Channel)(nil), "proto.Channel")
-	proto.RegisterType((*Provider)(nil), "proto.Provider")
-	proto.RegisterMapType((map[string]string)(nil), "proto.Provider.ParamsEntry")
-	proto.RegisterType((*MessagePutRequest)(nil), "proto.MessagePutRequest")
-	proto.RegisterType((*MessagePutDataResponse)(nil), "proto.MessagePutDataResponse")
-	proto.RegisterType((*MessagePutResponse)(nil), "proto.MessagePutResponse")
-	proto.RegisterType((*MessageGetRequest)(nil), "proto.MessageGetRequest")
-	proto.RegisterType((*MessageGetResponse)(nil), "proto.MessageGetResponse")
-	proto.RegisterType((*MessageUpdateRequest)(nil), "proto.MessageUpdateRequest")
-	proto.RegisterType((*MessageUpdateResponse)(nil), "proto.MessageUpdateResponse")
-	proto.RegisterType((*MessageCancelRequest)(nil), "proto.MessageCancelRequest")
-	proto.RegisterType((*MessageCancelResponse)(nil), "proto.MessageCancelResponse")
-}
-
-func init() { proto.RegisterFile("proto/messages.proto", fileDescriptor_346d92f49d8efbd3) }
-
-var fileDescriptor_346d92f49d8efbd3 = []byte{
-	// 535 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0xd1, 0x8a, 0xd3, 0x40,
-	0x14, 0x6d, 0x92, 0xa6, 0xdd, 0xde, 0xe2, 0xba, 0x0e, 0x71, 0x89, 0x5d, 0x85, 0x32, 0x82, 0x14,
-	0xc1, 0x8a, 0xdd, 0x17, 0x15, 0x05, 0xa1, 0x96, 0x7d, 0x12, 0xca, 0x14, 0x3f, 0x60, 0x4c, 0x2e,
-	0xb6, 0x98, 0x26, 0x71, 0x66, 0x52, 0xe8, 0xa3, 0x3f, 0xe0, 0x17, 0xf8, 0xb1, 0x92, 0xc9, 0x24,
-	0x4d, 0xd2, 0x16, 0x71, 0x9f, 0x72, 0xef, 0xdc, 0x7b, 0xce, 0x3d, 0x9c, 0x13, 0xf0, 0x52, 0x91,
-	0xa8, 0xe4, 0xf5, 0x16, 0xa5, 0xe4, 0xdf, 0x51, 0x4e, 0x75, 0x4b, 0x5c, 0xfd, 0xa1, 0x1f, 0xe1,
-	0xc1, 0x17, 0x33, 0x58, 0x08, 0x91, 0x08, 0x42, 0xa0, 0x1b, 0x24, 0x21, 0xfa, 0xd6, 0xd8, 0x9a,
-	0xb8, 0x4c, 0xd7, 0xc4, 0x87, 0xbe, 0x41, 0xfb, 0xf6, 0xd8, 0x9a, 0x0c, 0x58, 0xd9, 0xd2, 0x5f,
-	0x16, 0xf4, 0x0d, 0x9e, 0x5c, 0x82, 0xbd, 0x09, 0x35, 0x6e, 0xc0, 0xec, 0x4d, 0x98, 0xa3, 0x82,
-	0x35, 0x8f, 0x63, 0x8c, 0x4a, 0x94, 0x69, 0xc9, 0x08, 0x2e, 0x52, 0x91, 0xec, 0x36, 0x21, 0x0a,
-	0xdf, 0xd1, 0xa3, 0xaa, 0xd7, 0xa8, 0x24, 0x56, 0x18, 0x2b, 0xbf, 0x6b, 0x50, 0x45, 0x4b, 0xae,
-	0xa1, 0x27, 0x15, 0x57, 0x99, 0xf4, 0x5d, 0x3d, 0x30, 0x1d, 0x55, 0xd0, 0x9f, 0x1b, 0x62, 0x02,
-	0xdd, 0x98, 0x6f, 0xd1, 0x88, 0xd0, 0x35, 0x79, 0x05, 0x83, 0x92, 0x5c, 0xfa, 0xf6, 0xd8, 0x99,
-	0x0c, 0x67, 0x0f, 0x0b, 0x0f, 0xa6, 0x4b, 0xf3, 0xce, 0x0e, 0x1b, 0x39, 0xc5, 0x3a, 0x91, 0xca,
-	0xe8, 0xd2, 0x75, 0xfe, 0x96, 0x26, 0xa2, 0x14, 0xa4, 0x6b, 0xfa, 0xdb, 0x82, 0x8b, 0x12, 0x7f,
-	0xf2, 0xee, 0x2d, 0xf4, 0x52, 0x2e, 0xf8, 0xb6, 0x3c, 0x7a, 0xd3, 0x3a, 0x3a, 0x5d, 0xea, 0xe9,
-	0x22, 0x56, 0x62, 0xcf, 0xcc, 0xea, 0xe8, 0x1d, 0x0c, 0x6b, 0xcf, 0xe4, 0x0a, 0x9c, 0x1f, 0xb8,
-	0x37, 0xb4, 0x79, 0x49, 0x3c, 0x70, 0x77, 0x3c, 0xca, 0xca, 0x20, 0x8a, 0xe6, 0xbd, 0xfd, 0xd6,
-	0xa2, 0x7b, 0x78, 0x64, 0x92, 0x58, 0x66, 0x8a, 0xe1, 0xcf, 0x0c, 0xa5, 0xaa, 0x67, 0x60, 0x9d,
-	0xcf, 0xc0, 0x3e, 0x9f, 0x81, 0xd3, 0xcc, 0xc0, 0x03, 0x37, 0xc4, 0x88, 0xef, 0xb5, 0x15, 0x0e,
-	0x2b, 0x1a, 0x3a, 0x81, 0xeb, 0xc3, 0xe9, 0xcf, 0x5c, 0x71, 0x86, 0x32, 0x4d, 0x62, 0x79, 0xf4,
-	0x4f, 0x50, 0x09, 0xa4, 0x2e, 0xd2, 0x6c, 0xbd, 0x81, 0x6e, 0xc8, 0x15, 0xd7, 0x7b, 0xc3, 0xd9,
-	0x33, 0x63, 0xd4, 0x69, 0x4a, 0xa6, 0x57, 0xc9, 0x4b, 0x70, 0x31, 0xff, 0x5f, 0xb5, 0xf6, 0xe1,
-	0xcc, 0x6b, 0x62, 0x8a, 0x7f, 0x99, 0x15, 0x2b, 0xf4, 0x79, 0xe5, 0xcc, 0x1d, 0x56, 0xce, 0xb4,
-	0x95, 0x85, 0x95, 0x32, 0xbd, 0x64, 0x94, 0xd1, 0x86, 0xb2, 0xcb, 0xe6, 0x95, 0x7b, 0x48, 0xf9,
-	0x04, 0x9e, 0x79, 0xff, 0x9a, 0x86, 0x5c, 0xe1, 0x19, 0x35, 0xf5, 0x04, 0xec, 0x46, 0x02, 0x74,
-	0x0e, 0x8f, 0x5b, 0x0c, 0x46, 0x6a, 0x25, 0xc3, 0xfa, 0xb7, 0x8c, 0x17, 0x95, 0x8c, 0x39, 0x8f,
-	0x03, 0x8c, 0xce, 0x99, 0x72, 0x38, 0x56, 0xee, 0xfd, 0xff, 0xb1, 0xd9, 0x1f, 0x1b, 0xae, 0x56,
-	0xc1, 0x1a, 0xc3, 0x2c, 0x42, 0xb1, 0x42, 0xb1, 0xdb, 0x04, 0x48, 0x3e, 0x80, 0xb3, 0xcc, 0x14,
-	0xf1, 0x8f, 0xb2, 0x36, 0x52, 0x46, 0x4f, 0x4e, 0x4c, 0x8a, 0xe3, 0xb4, 0x93, 0xa3, 0xef, 0xf0,
-	0x08, 0x7d, 0x48, 0xb7, 0x8d, 0xae, 0x45, 0x4a, 0x3b, 0x64, 0x01, 0xbd, 0xc2, 0x3b, 0x72, 0xd3,
-	0x5c, 0x6b, 0x64, 0x32, 0x7a, 0x7a, 0x7a, 0x58, 0xa7, 0x29, 0x5c, 0x69, 0xd3, 0x34, 0x3c, 0x6d,
-	0xd3, 0x34, 0x8d, 0xa4, 0x9d, 0x6f, 0x3d, 0x3d, 0xbe, 0xfd, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xd9,
-	0xae, 0xf5, 0x28, 0xa7, 0x05, 0x00, 0x00,
+func (x *MessageGetRequest) Reset() {
+	*x = MessageGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (x *MessageGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (*MessageGetRequest) ProtoMessage() {}
 
-// SchedulerServiceClient is the client API for SchedulerService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type SchedulerServiceClient interface {
-	Put(ctx context.Context, in *MessagePutRequest, opts ...grpc.CallOption) (*MessagePutResponse, error)
-	Get(ctx context.Context, in *MessageGetRequest, opts ...grpc.CallOption) (*MessageGetResponse, error)
-	Update(ctx context.Context, in *MessageUpdateRequest, opts ...grpc.CallOption) (*MessageUpdateResponse, error)
-	Cancel(ctx context.Context, in *MessageCancelRequest, opts ...grpc.CallOption) (*MessageCancelResponse, error)
+func (x *MessageGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type schedulerServiceClient struct {
-	cc *grpc.ClientConn
+// Deprecated: Use MessageGetRequest.ProtoReflect.Descriptor instead.
+func (*MessageGetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{11}
 }
 
-func NewSchedulerServiceClient(cc *grpc.ClientConn) SchedulerServiceClient {
-	return &schedulerServiceClient{cc}
+func (x *MessageGetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (c *schedulerServiceClient) Put(ctx context.Context, in *MessagePutRequest, opts ...grpc.CallOption) (*MessagePutResponse, error) {
-	out := new(MessagePutResponse)
-	err := c.cc.Invoke(ctx, "/proto.SchedulerService/Put", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *MessageGetRequest) GetWithHistory() bool {
+	if x != nil {
+		return x.WithHistory
 	}
-	return out, nil
+	return false
 }
 
-func (c *schedulerServiceClient) Get(ctx context.Context, in *MessageGetRequest, opts ...grpc.CallOption) (*MessageGetResponse, error) {
-	out := new(MessageGetResponse)
-	err := c.cc.Invoke(ctx, "/proto.SchedulerService/Get", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *MessageGetRequest) GetWithVersions() bool {
+	if x != nil {
+		return x.WithVersions
 	}
-	return out, nil
+	return false
+}
+
+type MessageGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *Message       `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MessageGetResponse) Reset() {
+	*x = MessageGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageGetResponse) ProtoMessage() {}
+
+func (x *MessageGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageGetResponse.ProtoReflect.Descriptor instead.
+func (*MessageGetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MessageGetResponse) GetData() *Message {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MessageGetResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type MessageUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// revision is the one of the message read by the client, the update
+	// fails with Aborted when the message has changed since.
+	Revision int32 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// any_content replaces content on the messages of type
+	// application/protobuf.
+	AnyContent *anypb.Any `protobuf:"bytes,4,opt,name=any_content,json=anyContent,proto3" json:"any_content,omitempty"`
+}
+
+func (x *MessageUpdateRequest) Reset() {
+	*x = MessageUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageUpdateRequest) ProtoMessage() {}
+
+func (x *MessageUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageUpdateRequest.ProtoReflect.Descriptor instead.
+func (*MessageUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MessageUpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageUpdateRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *MessageUpdateRequest) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *MessageUpdateRequest) GetAnyContent() *anypb.Any {
+	if x != nil {
+		return x.AnyContent
+	}
+	return nil
+}
+
+type MessageUpdateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error *MessagesError `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MessageUpdateResponse) Reset() {
+	*x = MessageUpdateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageUpdateResponse) ProtoMessage() {}
+
+func (x *MessageUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageUpdateResponse.ProtoReflect.Descriptor instead.
+func (*MessageUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MessageUpdateResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type MessageCancelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// revision is the one of the message read by the client, the cancel
+	// fails with Aborted when the message has changed since.
+	Revision int32 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+}
+
+func (x *MessageCancelRequest) Reset() {
+	*x = MessageCancelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageCancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageCancelRequest) ProtoMessage() {}
+
+func (x *MessageCancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageCancelRequest.ProtoReflect.Descriptor instead.
+func (*MessageCancelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MessageCancelRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageCancelRequest) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type MessageCancelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error *MessagesError `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MessageCancelResponse) Reset() {
+	*x = MessageCancelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageCancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageCancelResponse) ProtoMessage() {}
+
+func (x *MessageCancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageCancelResponse.ProtoReflect.Descriptor instead.
+func (*MessageCancelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MessageCancelResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type MessageGetHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *MessageGetHistoryRequest) Reset() {
+	*x = MessageGetHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageGetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageGetHistoryRequest) ProtoMessage() {}
+
+func (x *MessageGetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageGetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*MessageGetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MessageGetHistoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type MessageGetHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  []*StatusTransition `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError      `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MessageGetHistoryResponse) Reset() {
+	*x = MessageGetHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageGetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageGetHistoryResponse) ProtoMessage() {}
+
+func (x *MessageGetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageGetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*MessageGetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MessageGetHistoryResponse) GetData() []*StatusTransition {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MessageGetHistoryResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type MessageExportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Status  string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// since and until are unix times in milliseconds.
+	Since    int64  `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until    int64  `protobuf:"varint,4,opt,name=until,proto3" json:"until,omitempty"`
+	Archived bool   `protobuf:"varint,5,opt,name=archived,proto3" json:"archived,omitempty"`
+	Format   string `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+}
+
+func (x *MessageExportRequest) Reset() {
+	*x = MessageExportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageExportRequest) ProtoMessage() {}
+
+func (x *MessageExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageExportRequest.ProtoReflect.Descriptor instead.
+func (*MessageExportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MessageExportRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *MessageExportRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MessageExportRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *MessageExportRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *MessageExportRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *MessageExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type MessageExportDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files []string `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	Count int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *MessageExportDataResponse) Reset() {
+	*x = MessageExportDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageExportDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageExportDataResponse) ProtoMessage() {}
+
+func (x *MessageExportDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageExportDataResponse.ProtoReflect.Descriptor instead.
+func (*MessageExportDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MessageExportDataResponse) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *MessageExportDataResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type MessageExportResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *MessageExportDataResponse `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError             `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MessageExportResponse) Reset() {
+	*x = MessageExportResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageExportResponse) ProtoMessage() {}
+
+func (x *MessageExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageExportResponse.ProtoReflect.Descriptor instead.
+func (*MessageExportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MessageExportResponse) GetData() *MessageExportDataResponse {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MessageExportResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type BackupRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *BackupRequest) Reset() {
+	*x = BackupRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupRequest) ProtoMessage() {}
+
+func (x *BackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupRequest.ProtoReflect.Descriptor instead.
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{22}
+}
+
+// BackupChunk is a piece of the gzip compressed tar written by the backup.
+type BackupChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *BackupChunk) Reset() {
+	*x = BackupChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackupChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupChunk) ProtoMessage() {}
+
+func (x *BackupChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupChunk.ProtoReflect.Descriptor instead.
+func (*BackupChunk) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BackupChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ChannelGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *ChannelGetRequest) Reset() {
+	*x = ChannelGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelGetRequest) ProtoMessage() {}
+
+func (x *ChannelGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelGetRequest.ProtoReflect.Descriptor instead.
+func (*ChannelGetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ChannelGetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ChannelGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// data has the secret values of the params redacted.
+	Data  *Channel       `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ChannelGetResponse) Reset() {
+	*x = ChannelGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelGetResponse) ProtoMessage() {}
+
+func (x *ChannelGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelGetResponse.ProtoReflect.Descriptor instead.
+func (*ChannelGetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ChannelGetResponse) GetData() *Channel {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChannelGetResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type Template struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// engine is text (default) or html.
+	Engine string `protobuf:"bytes,4,opt,name=engine,proto3" json:"engine,omitempty"`
+	Body   string `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	// content_type of the rendered content, text/plain or application/json.
+	ContentType string `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// client is the id of the authenticated client that stored the version.
+	Client string `protobuf:"bytes,7,opt,name=client,proto3" json:"client,omitempty"`
+	// time is the unix time in milliseconds.
+	Time int64 `protobuf:"varint,8,opt,name=time,proto3" json:"time,omitempty"`
+	// rollback_of is the version restored by a rollback.
+	RollbackOf int32 `protobuf:"varint,9,opt,name=rollback_of,json=rollbackOf,proto3" json:"rollback_of,omitempty"`
+	// locale is the BCP 47 tag of the variant, empty for the fallback one.
+	Locale string `protobuf:"bytes,10,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *Template) Reset() {
+	*x = Template{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Template) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Template) ProtoMessage() {}
+
+func (x *Template) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Template.ProtoReflect.Descriptor instead.
+func (*Template) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Template) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Template) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Template) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Template) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *Template) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Template) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Template) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *Template) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Template) GetRollbackOf() int32 {
+	if x != nil {
+		return x.RollbackOf
+	}
+	return 0
+}
+
+func (x *Template) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplateCreateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Name        string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Engine      string `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`
+	Body        string `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	ContentType string `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Locale      string `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *TemplateCreateRequest) Reset() {
+	*x = TemplateCreateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateCreateRequest) ProtoMessage() {}
+
+func (x *TemplateCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateCreateRequest.ProtoReflect.Descriptor instead.
+func (*TemplateCreateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TemplateCreateRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *TemplateCreateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateCreateRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *TemplateCreateRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *TemplateCreateRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *TemplateCreateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplateCreateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *Template      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *TemplateCreateResponse) Reset() {
+	*x = TemplateCreateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateCreateResponse) ProtoMessage() {}
+
+func (x *TemplateCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateCreateResponse.ProtoReflect.Descriptor instead.
+func (*TemplateCreateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TemplateCreateResponse) GetData() *Template {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TemplateCreateResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type TemplateGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// version 0 is the latest one.
+	Version      int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	WithVersions bool   `protobuf:"varint,4,opt,name=with_versions,json=withVersions,proto3" json:"with_versions,omitempty"`
+	Locale       string `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *TemplateGetRequest) Reset() {
+	*x = TemplateGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateGetRequest) ProtoMessage() {}
+
+func (x *TemplateGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateGetRequest.ProtoReflect.Descriptor instead.
+func (*TemplateGetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TemplateGetRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *TemplateGetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateGetRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TemplateGetRequest) GetWithVersions() bool {
+	if x != nil {
+		return x.WithVersions
+	}
+	return false
+}
+
+func (x *TemplateGetRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplateGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *Template      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// versions are every version, from the oldest to the newest.
+	Versions []*Template `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+}
+
+func (x *TemplateGetResponse) Reset() {
+	*x = TemplateGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateGetResponse) ProtoMessage() {}
+
+func (x *TemplateGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateGetResponse.ProtoReflect.Descriptor instead.
+func (*TemplateGetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TemplateGetResponse) GetData() *Template {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TemplateGetResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *TemplateGetResponse) GetVersions() []*Template {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type TemplatePreviewRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel   string           `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Name      string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version   int32            `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Variables *structpb.Struct `protobuf:"bytes,4,opt,name=variables,proto3" json:"variables,omitempty"`
+	// locale of the recipient, resolved as on Put.
+	Locale string `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *TemplatePreviewRequest) Reset() {
+	*x = TemplatePreviewRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplatePreviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplatePreviewRequest) ProtoMessage() {}
+
+func (x *TemplatePreviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplatePreviewRequest.ProtoReflect.Descriptor instead.
+func (*TemplatePreviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TemplatePreviewRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *TemplatePreviewRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplatePreviewRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TemplatePreviewRequest) GetVariables() *structpb.Struct {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *TemplatePreviewRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplatePreviewDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content     string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Version     int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// locale of the variant used.
+	Locale string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *TemplatePreviewDataResponse) Reset() {
+	*x = TemplatePreviewDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplatePreviewDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplatePreviewDataResponse) ProtoMessage() {}
+
+func (x *TemplatePreviewDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplatePreviewDataResponse.ProtoReflect.Descriptor instead.
+func (*TemplatePreviewDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TemplatePreviewDataResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *TemplatePreviewDataResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *TemplatePreviewDataResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TemplatePreviewDataResponse) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplatePreviewResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *TemplatePreviewDataResponse `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError               `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *TemplatePreviewResponse) Reset() {
+	*x = TemplatePreviewResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplatePreviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplatePreviewResponse) ProtoMessage() {}
+
+func (x *TemplatePreviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplatePreviewResponse.ProtoReflect.Descriptor instead.
+func (*TemplatePreviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TemplatePreviewResponse) GetData() *TemplatePreviewDataResponse {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TemplatePreviewResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type TemplateRollbackRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// version is restored as a new version.
+	Version int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Locale  string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *TemplateRollbackRequest) Reset() {
+	*x = TemplateRollbackRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateRollbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateRollbackRequest) ProtoMessage() {}
+
+func (x *TemplateRollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateRollbackRequest.ProtoReflect.Descriptor instead.
+func (*TemplateRollbackRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TemplateRollbackRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *TemplateRollbackRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateRollbackRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TemplateRollbackRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type TemplateRollbackResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data  *Template      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Error *MessagesError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *TemplateRollbackResponse) Reset() {
+	*x = TemplateRollbackResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_messages_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateRollbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateRollbackResponse) ProtoMessage() {}
+
+func (x *TemplateRollbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messages_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateRollbackResponse.ProtoReflect.Descriptor instead.
+func (*TemplateRollbackResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messages_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TemplateRollbackResponse) GetData() *Template {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TemplateRollbackResponse) GetError() *MessagesError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+var File_proto_messages_proto protoreflect.FileDescriptor
+
+var file_proto_messages_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61,
+	0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3d, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x93, 0x05, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x31, 0x0a, 0x07, 0x68, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x11,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x10, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x0d, 0x74, 0x72,
+	0x61, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a,
+	0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x74, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x1a, 0x3f, 0x0a, 0x11, 0x54, 0x72,
+	0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb0, 0x01, 0x0a, 0x0e,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x3c, 0x0a, 0x11, 0x65, 0x6e, 0x63, 0x72,
+	0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x65, 0x52, 0x10, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x5d,
+	0x0a, 0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65,
+	0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc6, 0x01,
+	0x0a, 0x10, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x22, 0xa2, 0x01, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2c, 0x0a,
+	0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x22, 0x5d, 0x0a, 0x0d, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04,
+	0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e,
+	0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x8e, 0x01, 0x0a, 0x08, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe9, 0x02, 0x0a, 0x11,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x6e,
+	0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x0a, 0x61, 0x6e, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a,
+	0x10, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69,
+	0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x28, 0x0a, 0x16, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x50, 0x75, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x73, 0x0a, 0x12, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x75, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x75, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x6b, 0x0a, 0x11, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x77,
+	0x69, 0x74, 0x68, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x23,
+	0x0a, 0x0d, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x64, 0x0a, 0x12, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x93, 0x01, 0x0a, 0x14, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x6e, 0x79, 0x5f,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x41, 0x6e, 0x79, 0x52, 0x0a, 0x61, 0x6e, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22,
+	0x43, 0x0a, 0x15, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x42, 0x0a, 0x14, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x43, 0x0a, 0x15, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x2a, 0x0a,
+	0x18, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x74, 0x0a, 0x19, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0xa8, 0x01, 0x0a, 0x14, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x47, 0x0a, 0x19, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x79, 0x0a, 0x15, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x0f,
+	0x0a, 0x0d, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x21, 0x0a, 0x0b, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0x27, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x64, 0x0a, 0x12, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x86, 0x02, 0x0a, 0x08, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x6f, 0x66,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x4f, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x15, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x21,
+	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x69, 0x0a, 0x16, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x99, 0x01, 0x0a, 0x12, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65,
+	0x22, 0x93, 0x01, 0x0a, 0x13, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2b, 0x0a, 0x08, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x08, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xaf, 0x01, 0x0a, 0x16, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x09, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x1b, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x7d, 0x0a, 0x17, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x36, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x79, 0x0a, 0x17, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x22, 0x6b, 0x0a, 0x18, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0xf7,
+	0x06, 0x0a, 0x10, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x3c, 0x0a, 0x03, 0x50, 0x75, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x50, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x3c, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x45, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
+	0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x51, 0x0a,
+	0x0a, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x47, 0x65, 0x74, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x47, 0x65, 0x74, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x45, 0x0a, 0x06, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x06, 0x42, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x43, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x54, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a,
+	0x0f, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x55, 0x0a, 0x10, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_messages_proto_rawDescOnce sync.Once
+	file_proto_messages_proto_rawDescData = file_proto_messages_proto_rawDesc
+)
+
+func file_proto_messages_proto_rawDescGZIP() []byte {
+	file_proto_messages_proto_rawDescOnce.Do(func() {
+		file_proto_messages_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_messages_proto_rawDescData)
+	})
+	return file_proto_messages_proto_rawDescData
+}
+
+var file_proto_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_proto_messages_proto_goTypes = []interface{}{
+	(*MessagesError)(nil),               // 0: proto.MessagesError
+	(*Message)(nil),                     // 1: proto.Message
+	(*ContentVersion)(nil),              // 2: proto.ContentVersion
+	(*Envelope)(nil),                    // 3: proto.Envelope
+	(*StatusTransition)(nil),            // 4: proto.StatusTransition
+	(*Channel)(nil),                     // 5: proto.Channel
+	(*ContentSchema)(nil),               // 6: proto.ContentSchema
+	(*Provider)(nil),                    // 7: proto.Provider
+	(*MessagePutRequest)(nil),           // 8: proto.MessagePutRequest
+	(*MessagePutDataResponse)(nil),      // 9: proto.MessagePutDataResponse
+	(*MessagePutResponse)(nil),          // 10: proto.MessagePutResponse
+	(*MessageGetRequest)(nil),           // 11: proto.MessageGetRequest
+	(*MessageGetResponse)(nil),          // 12: proto.MessageGetResponse
+	(*MessageUpdateRequest)(nil),        // 13: proto.MessageUpdateRequest
+	(*MessageUpdateResponse)(nil),       // 14: proto.MessageUpdateResponse
+	(*MessageCancelRequest)(nil),        // 15: proto.MessageCancelRequest
+	(*MessageCancelResponse)(nil),       // 16: proto.MessageCancelResponse
+	(*MessageGetHistoryRequest)(nil),    // 17: proto.MessageGetHistoryRequest
+	(*MessageGetHistoryResponse)(nil),   // 18: proto.MessageGetHistoryResponse
+	(*MessageExportRequest)(nil),        // 19: proto.MessageExportRequest
+	(*MessageExportDataResponse)(nil),   // 20: proto.MessageExportDataResponse
+	(*MessageExportResponse)(nil),       // 21: proto.MessageExportResponse
+	(*BackupRequest)(nil),               // 22: proto.BackupRequest
+	(*BackupChunk)(nil),                 // 23: proto.BackupChunk
+	(*ChannelGetRequest)(nil),           // 24: proto.ChannelGetRequest
+	(*ChannelGetResponse)(nil),          // 25: proto.ChannelGetResponse
+	(*Template)(nil),                    // 26: proto.Template
+	(*TemplateCreateRequest)(nil),       // 27: proto.TemplateCreateRequest
+	(*TemplateCreateResponse)(nil),      // 28: proto.TemplateCreateResponse
+	(*TemplateGetRequest)(nil),          // 29: proto.TemplateGetRequest
+	(*TemplateGetResponse)(nil),         // 30: proto.TemplateGetResponse
+	(*TemplatePreviewRequest)(nil),      // 31: proto.TemplatePreviewRequest
+	(*TemplatePreviewDataResponse)(nil), // 32: proto.TemplatePreviewDataResponse
+	(*TemplatePreviewResponse)(nil),     // 33: proto.TemplatePreviewResponse
+	(*TemplateRollbackRequest)(nil),     // 34: proto.TemplateRollbackRequest
+	(*TemplateRollbackResponse)(nil),    // 35: proto.TemplateRollbackResponse
+	nil,                                 // 36: proto.Message.TraceContextEntry
+	nil,                                 // 37: proto.Provider.ParamsEntry
+	(*anypb.Any)(nil),                   // 38: google.protobuf.Any
+	(*structpb.Struct)(nil),             // 39: google.protobuf.Struct
+}
+var file_proto_messages_proto_depIdxs = []int32{
+	4,  // 0: proto.Message.history:type_name -> proto.StatusTransition
+	3,  // 1: proto.Message.encrypted_content:type_name -> proto.Envelope
+	36, // 2: proto.Message.trace_context:type_name -> proto.Message.TraceContextEntry
+	2,  // 3: proto.Message.versions:type_name -> proto.ContentVersion
+	3,  // 4: proto.ContentVersion.encrypted_content:type_name -> proto.Envelope
+	7,  // 5: proto.Channel.providers:type_name -> proto.Provider
+	6,  // 6: proto.Channel.schema:type_name -> proto.ContentSchema
+	37, // 7: proto.Provider.params:type_name -> proto.Provider.ParamsEntry
+	38, // 8: proto.MessagePutRequest.any_content:type_name -> google.protobuf.Any
+	39, // 9: proto.MessagePutRequest.variables:type_name -> google.protobuf.Struct
+	9,  // 10: proto.MessagePutResponse.data:type_name -> proto.MessagePutDataResponse
+	0,  // 11: proto.MessagePutResponse.error:type_name -> proto.MessagesError
+	1,  // 12: proto.MessageGetResponse.data:type_name -> proto.Message
+	0,  // 13: proto.MessageGetResponse.error:type_name -> proto.MessagesError
+	38, // 14: proto.MessageUpdateRequest.any_content:type_name -> google.protobuf.Any
+	0,  // 15: proto.MessageUpdateResponse.error:type_name -> proto.MessagesError
+	0,  // 16: proto.MessageCancelResponse.error:type_name -> proto.MessagesError
+	4,  // 17: proto.MessageGetHistoryResponse.data:type_name -> proto.StatusTransition
+	0,  // 18: proto.MessageGetHistoryResponse.error:type_name -> proto.MessagesError
+	20, // 19: proto.MessageExportResponse.data:type_name -> proto.MessageExportDataResponse
+	0,  // 20: proto.MessageExportResponse.error:type_name -> proto.MessagesError
+	5,  // 21: proto.ChannelGetResponse.data:type_name -> proto.Channel
+	0,  // 22: proto.ChannelGetResponse.error:type_name -> proto.MessagesError
+	26, // 23: proto.TemplateCreateResponse.data:type_name -> proto.Template
+	0,  // 24: proto.TemplateCreateResponse.error:type_name -> proto.MessagesError
+	26, // 25: proto.TemplateGetResponse.data:type_name -> proto.Template
+	0,  // 26: proto.TemplateGetResponse.error:type_name -> proto.MessagesError
+	26, // 27: proto.TemplateGetResponse.versions:type_name -> proto.Template
+	39, // 28: proto.TemplatePreviewRequest.variables:type_name -> google.protobuf.Struct
+	32, // 29: proto.TemplatePreviewResponse.data:type_name -> proto.TemplatePreviewDataResponse
+	0,  // 30: proto.TemplatePreviewResponse.error:type_name -> proto.MessagesError
+	26, // 31: proto.TemplateRollbackResponse.data:type_name -> proto.Template
+	0,  // 32: proto.TemplateRollbackResponse.error:type_name -> proto.MessagesError
+	8,  // 33: proto.SchedulerService.Put:input_type -> proto.MessagePutRequest
+	11, // 34: proto.SchedulerService.Get:input_type -> proto.MessageGetRequest
+	13, // 35: proto.SchedulerService.Update:input_type -> proto.MessageUpdateRequest
+	15, // 36: proto.SchedulerService.Cancel:input_type -> proto.MessageCancelRequest
+	17, // 37: proto.SchedulerService.GetHistory:input_type -> proto.MessageGetHistoryRequest
+	19, // 38: proto.SchedulerService.Export:input_type -> proto.MessageExportRequest
+	22, // 39: proto.SchedulerService.Backup:input_type -> proto.BackupRequest
+	24, // 40: proto.SchedulerService.GetChannel:input_type -> proto.ChannelGetRequest
+	27, // 41: proto.SchedulerService.CreateTemplate:input_type -> proto.TemplateCreateRequest
+	29, // 42: proto.SchedulerService.GetTemplate:input_type -> proto.TemplateGetRequest
+	31, // 43: proto.SchedulerService.PreviewTemplate:input_type -> proto.TemplatePreviewRequest
+	34, // 44: proto.SchedulerService.RollbackTemplate:input_type -> proto.TemplateRollbackRequest
+	10, // 45: proto.SchedulerService.Put:output_type -> proto.MessagePutResponse
+	12, // 46: proto.SchedulerService.Get:output_type -> proto.MessageGetResponse
+	14, // 47: proto.SchedulerService.Update:output_type -> proto.MessageUpdateResponse
+	16, // 48: proto.SchedulerService.Cancel:output_type -> proto.MessageCancelResponse
+	18, // 49: proto.SchedulerService.GetHistory:output_type -> proto.MessageGetHistoryResponse
+	21, // 50: proto.SchedulerService.Export:output_type -> proto.MessageExportResponse
+	23, // 51: proto.SchedulerService.Backup:output_type -> proto.BackupChunk
+	25, // 52: proto.SchedulerService.GetChannel:output_type -> proto.ChannelGetResponse
+	28, // 53: proto.SchedulerService.CreateTemplate:output_type -> proto.TemplateCreateResponse
+	30, // 54: proto.SchedulerService.GetTemplate:output_type -> proto.TemplateGetResponse
+	33, // 55: proto.SchedulerService.PreviewTemplate:output_type -> proto.TemplatePreviewResponse
+	35, // 56: proto.SchedulerService.RollbackTemplate:output_type -> proto.TemplateRollbackResponse
+	45, // [45:57] is the sub-list for method output_type
+	33, // [33:45] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
+}
+
+func init() { file_proto_messages_proto_init() }
+func file_proto_messages_proto_init() {
+	if File_proto_messages_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_messages_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagesError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Envelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusTransition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Channel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentSchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Provider); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagePutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagePutDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagePutResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageUpdateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageCancelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageCancelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageGetHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageGetHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageExportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageExportDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageExportResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Template); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateCreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateCreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplatePreviewRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplatePreviewDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplatePreviewResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateRollbackRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_messages_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateRollbackResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_messages_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   38,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_messages_proto_goTypes,
+		DependencyIndexes: file_proto_messages_proto_depIdxs,
+		MessageInfos:      file_proto_messages_proto_msgTypes,
+	}.Build()
+	File_proto_messages_proto = out.File
+	file_proto_messages_proto_rawDesc = nil
+	file_proto_messages_proto_goTypes = nil
+	file_proto_messages_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SchedulerServiceClient is the client API for SchedulerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SchedulerServiceClient interface {
+	Put(ctx context.Context, in *MessagePutRequest, opts ...grpc.CallOption) (*MessagePutResponse, error)
+	Get(ctx context.Context, in *MessageGetRequest, opts ...grpc.CallOption) (*MessageGetResponse, error)
+	Update(ctx context.Context, in *MessageUpdateRequest, opts ...grpc.CallOption) (*MessageUpdateResponse, error)
+	Cancel(ctx context.Context, in *MessageCancelRequest, opts ...grpc.CallOption) (*MessageCancelResponse, error)
+	GetHistory(ctx context.Context, in *MessageGetHistoryRequest, opts ...grpc.CallOption) (*MessageGetHistoryResponse, error)
+	Export(ctx context.Context, in *MessageExportRequest, opts ...grpc.CallOption) (*MessageExportResponse, error)
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (SchedulerService_BackupClient, error)
+	GetChannel(ctx context.Context, in *ChannelGetRequest, opts ...grpc.CallOption) (*ChannelGetResponse, error)
+	CreateTemplate(ctx context.Context, in *TemplateCreateRequest, opts ...grpc.CallOption) (*TemplateCreateResponse, error)
+	GetTemplate(ctx context.Context, in *TemplateGetRequest, opts ...grpc.CallOption) (*TemplateGetResponse, error)
+	PreviewTemplate(ctx context.Context, in *TemplatePreviewRequest, opts ...grpc.CallOption) (*TemplatePreviewResponse, error)
+	RollbackTemplate(ctx context.Context, in *TemplateRollbackRequest, opts ...grpc.CallOption) (*TemplateRollbackResponse, error)
+}
+
+type schedulerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerServiceClient(cc grpc.ClientConnInterface) SchedulerServiceClient {
+	return &schedulerServiceClient{cc}
+}
+
+func (c *schedulerServiceClient) Put(ctx context.Context, in *MessagePutRequest, opts ...grpc.CallOption) (*MessagePutResponse, error) {
+	out := new(MessagePutResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/Put", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) Get(ctx context.Context, in *MessageGetRequest, opts ...grpc.CallOption) (*MessageGetResponse, error) {
+	out := new(MessageGetResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *schedulerServiceClient) Update(ctx context.Context, in *MessageUpdateRequest, opts ...grpc.CallOption) (*MessageUpdateResponse, error) {
@@ -768,12 +3567,156 @@ func (c *schedulerServiceClient) Cancel(ctx context.Context, in *MessageCancelRe
 	return out, nil
 }
 
+func (c *schedulerServiceClient) GetHistory(ctx context.Context, in *MessageGetHistoryRequest, opts ...grpc.CallOption) (*MessageGetHistoryResponse, error) {
+	out := new(MessageGetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/GetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) Export(ctx context.Context, in *MessageExportRequest, opts ...grpc.CallOption) (*MessageExportResponse, error) {
+	out := new(MessageExportResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/Export", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (SchedulerService_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SchedulerService_serviceDesc.Streams[0], "/proto.SchedulerService/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schedulerServiceBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchedulerService_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type schedulerServiceBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *schedulerServiceBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schedulerServiceClient) GetChannel(ctx context.Context, in *ChannelGetRequest, opts ...grpc.CallOption) (*ChannelGetResponse, error) {
+	out := new(ChannelGetResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/GetChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) CreateTemplate(ctx context.Context, in *TemplateCreateRequest, opts ...grpc.CallOption) (*TemplateCreateResponse, error) {
+	out := new(TemplateCreateResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetTemplate(ctx context.Context, in *TemplateGetRequest, opts ...grpc.CallOption) (*TemplateGetResponse, error) {
+	out := new(TemplateGetResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/GetTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) PreviewTemplate(ctx context.Context, in *TemplatePreviewRequest, opts ...grpc.CallOption) (*TemplatePreviewResponse, error) {
+	out := new(TemplatePreviewResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/PreviewTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) RollbackTemplate(ctx context.Context, in *TemplateRollbackRequest, opts ...grpc.CallOption) (*TemplateRollbackResponse, error) {
+	out := new(TemplateRollbackResponse)
+	err := c.cc.Invoke(ctx, "/proto.SchedulerService/RollbackTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SchedulerServiceServer is the server API for SchedulerService service.
 type SchedulerServiceServer interface {
 	Put(context.Context, *MessagePutRequest) (*MessagePutResponse, error)
 	Get(context.Context, *MessageGetRequest) (*MessageGetResponse, error)
 	Update(context.Context, *MessageUpdateRequest) (*MessageUpdateResponse, error)
 	Cancel(context.Context, *MessageCancelRequest) (*MessageCancelResponse, error)
+	GetHistory(context.Context, *MessageGetHistoryRequest) (*MessageGetHistoryResponse, error)
+	Export(context.Context, *MessageExportRequest) (*MessageExportResponse, error)
+	Backup(*BackupRequest, SchedulerService_BackupServer) error
+	GetChannel(context.Context, *ChannelGetRequest) (*ChannelGetResponse, error)
+	CreateTemplate(context.Context, *TemplateCreateRequest) (*TemplateCreateResponse, error)
+	GetTemplate(context.Context, *TemplateGetRequest) (*TemplateGetResponse, error)
+	PreviewTemplate(context.Context, *TemplatePreviewRequest) (*TemplatePreviewResponse, error)
+	RollbackTemplate(context.Context, *TemplateRollbackRequest) (*TemplateRollbackResponse, error)
+}
+
+// UnimplementedSchedulerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSchedulerServiceServer struct {
+}
+
+func (*UnimplementedSchedulerServiceServer) Put(context.Context, *MessagePutRequest) (*MessagePutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) Get(context.Context, *MessageGetRequest) (*MessageGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) Update(context.Context, *MessageUpdateRequest) (*MessageUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) Cancel(context.Context, *MessageCancelRequest) (*MessageCancelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) GetHistory(context.Context, *MessageGetHistoryRequest) (*MessageGetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) Export(context.Context, *MessageExportRequest) (*MessageExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) Backup(*BackupRequest, SchedulerService_BackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) GetChannel(context.Context, *ChannelGetRequest) (*ChannelGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChannel not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) CreateTemplate(context.Context, *TemplateCreateRequest) (*TemplateCreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) GetTemplate(context.Context, *TemplateGetRequest) (*TemplateGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) PreviewTemplate(context.Context, *TemplatePreviewRequest) (*TemplatePreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewTemplate not implemented")
+}
+func (*UnimplementedSchedulerServiceServer) RollbackTemplate(context.Context, *TemplateRollbackRequest) (*TemplateRollbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackTemplate not implemented")
 }
 
 func RegisterSchedulerServiceServer(s *grpc.Server, srv SchedulerServiceServer) {
@@ -852,6 +3795,153 @@ func _SchedulerService_Cancel_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SchedulerService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageGetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetHistory(ctx, req.(*MessageGetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/Export",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).Export(ctx, req.(*MessageExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchedulerServiceServer).Backup(m, &schedulerServiceBackupServer{stream})
+}
+
+type SchedulerService_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type schedulerServiceBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *schedulerServiceBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SchedulerService_GetChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/GetChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetChannel(ctx, req.(*ChannelGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).CreateTemplate(ctx, req.(*TemplateCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/GetTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetTemplate(ctx, req.(*TemplateGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_PreviewTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplatePreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).PreviewTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/PreviewTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).PreviewTemplate(ctx, req.(*TemplatePreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_RollbackTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateRollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RollbackTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SchedulerService/RollbackTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RollbackTemplate(ctx, req.(*TemplateRollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SchedulerService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.SchedulerService",
 	HandlerType: (*SchedulerServiceServer)(nil),
@@ -872,7 +3962,41 @@ var _SchedulerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Cancel",
 			Handler:    _SchedulerService_Cancel_Handler,
 		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _SchedulerService_GetHistory_Handler,
+		},
+		{
+			MethodName: "Export",
+			Handler:    _SchedulerService_Export_Handler,
+		},
+		{
+			MethodName: "GetChannel",
+			Handler:    _SchedulerService_GetChannel_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _SchedulerService_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _SchedulerService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "PreviewTemplate",
+			Handler:    _SchedulerService_PreviewTemplate_Handler,
+		},
+		{
+			MethodName: "RollbackTemplate",
+			Handler:    _SchedulerService_RollbackTemplate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _SchedulerService_Backup_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/messages.proto",
 }
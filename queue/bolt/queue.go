@@ -0,0 +1,273 @@
+package bolt
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/oklog/ulid"
+
+	db "github.com/microapis/messages-core/message/database"
+)
+
+var (
+	// QueueBucket ...
+	QueueBucket = []byte("queue")
+	// IndexBucket keys the key of every id of QueueBucket by the id.
+	IndexBucket = []byte("queue_index")
+)
+
+var _ queue.Queue = (*Queue)(nil)
+
+// Queue is a priority queue backed by a bolt bucket.
+//
+// The keys of the bucket are the binary ULIDs, which bolt keeps sorted
+// by their encoded time, so the first key is always the next to be sent.
+// The ids pushed by PushAt are keyed by the ULID with the time they are
+// due, and the value of every key is the id. The key of every id is kept
+// on IndexBucket, so an id is only queued once, as on the redis sorted
+// set, and is deleted without scanning the queue.
+type Queue struct {
+	Dst *db.BoltDatastore
+
+	// Tenant owns the queue, the default tenant keeps it on the root
+	// bucket.
+	Tenant string
+}
+
+// NewQueue ...
+func NewQueue(dst *db.BoltDatastore) (*Queue, error) {
+	pq := &Queue{
+		Dst:    dst,
+		Tenant: tenant.Default,
+	}
+	if err := pq.init(); err != nil {
+		return nil, err
+	}
+
+	return pq, nil
+}
+
+// For returns the queue of tenant, creating its bucket if it does not
+// exist.
+func (pq *Queue) For(tenantID string) (queue.Queue, error) {
+	tq := &Queue{
+		Dst:    pq.Dst,
+		Tenant: tenantID,
+	}
+	if err := tq.init(); err != nil {
+		return nil, err
+	}
+
+	return tq, nil
+}
+
+// init creates the buckets of the queue and indexes the ids queued
+// before the index, dropping the older entries of the ids queued twice.
+func (pq *Queue) init() error {
+	if err := pq.Dst.CreateBuckets(pq.Tenant, QueueBucket, IndexBucket); err != nil {
+		return err
+	}
+
+	return pq.Dst.DB.Update(func(tx *bolt.Tx) error {
+		b := db.Bucket(tx, pq.Tenant, QueueBucket)
+		ib := db.Bucket(tx, pq.Tenant, IndexBucket)
+		if b.Stats().KeyN == ib.Stats().KeyN {
+			return nil
+		}
+
+		var stale [][]byte
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			id, _, err := entry(k, v)
+			if err != nil {
+				return err
+			}
+			if old := ib.Get(id[:]); old != nil {
+				stale = append(stale, append([]byte(nil), old...))
+			}
+			if err := ib.Put(id[:], append([]byte(nil), k...)); err != nil {
+				return err
+			}
+		}
+		for _, k := range stale {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Push ...
+func (pq *Queue) Push(id ulid.ULID) error {
+	return pq.PushAt(id, ulid.Time(id.Time()))
+}
+
+// PushAt keys id by the ULID with its entropy and the time t, replacing
+// the key of id if it is already queued.
+func (pq *Queue) PushAt(id ulid.ULID, t time.Time) error {
+	return pq.Dst.DB.Update(func(tx *bolt.Tx) error {
+		return pq.put(tx, id, t)
+	})
+}
+
+// PushMax counts the ids and pushes id on the same transaction.
+func (pq *Queue) PushMax(id ulid.ULID, max int) error {
+	return pq.Dst.DB.Update(func(tx *bolt.Tx) error {
+		ib := db.Bucket(tx, pq.Tenant, IndexBucket)
+		if ib.Get(id[:]) == nil && ib.Stats().KeyN >= max {
+			return queue.ErrFull
+		}
+		return pq.put(tx, id, ulid.Time(id.Time()))
+	})
+}
+
+// put queues id due at t on tx.
+func (pq *Queue) put(tx *bolt.Tx, id ulid.ULID, t time.Time) error {
+	b := db.Bucket(tx, pq.Tenant, QueueBucket)
+	ib := db.Bucket(tx, pq.Tenant, IndexBucket)
+
+	if old := ib.Get(id[:]); old != nil {
+		if err := b.Delete(old); err != nil {
+			return err
+		}
+	}
+
+	k := id
+	if err := k.SetTime(ulid.Timestamp(t)); err != nil {
+		return err
+	}
+	if err := b.Put(k[:], []byte(id.String())); err != nil {
+		return err
+	}
+
+	return ib.Put(id[:], k[:])
+}
+
+// Peek ...
+func (pq *Queue) Peek() (*ulid.ULID, time.Time, error) {
+	var id *ulid.ULID
+	var due time.Time
+	err := pq.Dst.DB.View(func(tx *bolt.Tx) error {
+		k, v := db.Bucket(tx, pq.Tenant, QueueBucket).Cursor().First()
+		if k == nil {
+			return nil
+		}
+
+		var err error
+		if id, due, err = entry(k, v); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return id, due, nil
+}
+
+// entry returns the id of the key k with the value v and the time it is
+// due.
+func entry(k, v []byte) (*ulid.ULID, time.Time, error) {
+	var key ulid.ULID
+	if err := key.UnmarshalBinary(k); err != nil {
+		return nil, time.Time{}, err
+	}
+	id, err := ulid.Parse(string(v))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &id, ulid.Time(key.Time()), nil
+}
+
+// Pop ...
+func (pq *Queue) Pop() (*ulid.ULID, error) {
+	var id *ulid.ULID
+	err := pq.Dst.DB.Update(func(tx *bolt.Tx) error {
+		c := db.Bucket(tx, pq.Tenant, QueueBucket).Cursor()
+		k, v := c.First()
+		if k == nil {
+			return nil
+		}
+
+		var err error
+		if id, _, err = entry(k, v); err != nil {
+			return err
+		}
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		return db.Bucket(tx, pq.Tenant, IndexBucket).Delete(id[:])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// Delete looks up the key of id on the index.
+func (pq *Queue) Delete(id ulid.ULID) (bool, error) {
+	var found bool
+	err := pq.Dst.DB.Update(func(tx *bolt.Tx) error {
+		ib := db.Bucket(tx, pq.Tenant, IndexBucket)
+		k := ib.Get(id[:])
+		if k == nil {
+			return nil
+		}
+
+		found = true
+		if err := db.Bucket(tx, pq.Tenant, QueueBucket).Delete(k); err != nil {
+			return err
+		}
+		return ib.Delete(id[:])
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// Len ...
+func (pq *Queue) Len() (int, error) {
+	var n int
+	err := pq.Dst.DB.View(func(tx *bolt.Tx) error {
+		n = db.Bucket(tx, pq.Tenant, QueueBucket).Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Ping ...
+func (pq *Queue) Ping() error {
+	return pq.Dst.Ping()
+}
+
+// List ...
+func (pq *Queue) List() ([]ulid.ULID, error) {
+	ids := make([]ulid.ULID, 0)
+	err := pq.Dst.DB.View(func(tx *bolt.Tx) error {
+		return db.Bucket(tx, pq.Tenant, QueueBucket).ForEach(func(k, v []byte) error {
+			id, _, err := entry(k, v)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, *id)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,149 @@
+package bolt
+
+import (
+	"crypto/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/queue"
+	"github.com/oklog/ulid"
+
+	db "github.com/microapis/messages-core/message/database"
+)
+
+func newQueue(t *testing.T) *Queue {
+	t.Helper()
+	dst, err := db.NewBoltDatastore(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dst.DB.Close() })
+
+	pq, err := NewQueue(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pq
+}
+
+func TestPushAt(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	first := ulid.MustNew(ulid.Timestamp(now), rand.Reader)
+	second := ulid.MustNew(ulid.Timestamp(now.Add(time.Second)), rand.Reader)
+
+	pq := newQueue(t)
+	if err := pq.PushAt(first, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pq.Push(second); err != nil {
+		t.Fatal(err)
+	}
+
+	// first is due after second.
+	id, due, err := pq.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == nil || *id != second || !due.Equal(now.Add(time.Second)) {
+		t.Fatalf("Peek() = %v, %v, want %v, %v", id, due, second, now.Add(time.Second))
+	}
+
+	ids, err := pq.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ulid.ULID{second, first}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("List() = %v, want %v", ids, want)
+	}
+
+	tests := []struct {
+		id    ulid.ULID
+		found bool
+	}{
+		{first, true},
+		{first, false},
+		{second, true},
+	}
+	for _, tt := range tests {
+		found, err := pq.Delete(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != tt.found {
+			t.Errorf("Delete(%v) = %v, want %v", tt.id, found, tt.found)
+		}
+	}
+
+	if id, _, err := pq.Peek(); err != nil || id != nil {
+		t.Errorf("Peek() = %v, %v, want an empty queue", id, err)
+	}
+}
+
+func TestPushAtTwice(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	id := ulid.MustNew(ulid.Timestamp(now), rand.Reader)
+
+	pq := newQueue(t)
+	for _, at := range []time.Time{now.Add(time.Hour), now.Add(time.Minute)} {
+		if err := pq.PushAt(id, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the id is queued once, due at the last push.
+	if n, err := pq.Len(); err != nil || n != 1 {
+		t.Errorf("Len() = %d, %v, want 1", n, err)
+	}
+	got, due, err := pq.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != id || !due.Equal(now.Add(time.Minute)) {
+		t.Errorf("Peek() = %v, %v, want %v, %v", got, due, id, now.Add(time.Minute))
+	}
+
+	if found, err := pq.Delete(id); err != nil || !found {
+		t.Errorf("Delete() = %v, %v, want true", found, err)
+	}
+	if n, err := pq.Len(); err != nil || n != 0 {
+		t.Errorf("Len() after Delete() = %d, %v, want 0", n, err)
+	}
+
+	// a popped id is no longer indexed.
+	if err := pq.Push(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pq.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := pq.Delete(id); err != nil || found {
+		t.Errorf("Delete() after Pop() = %v, %v, want false", found, err)
+	}
+}
+
+func TestPushMax(t *testing.T) {
+	now := time.Now()
+	first := ulid.MustNew(ulid.Timestamp(now), rand.Reader)
+	second := ulid.MustNew(ulid.Timestamp(now), rand.Reader)
+
+	pq := newQueue(t)
+	tests := []struct {
+		id  ulid.ULID
+		err error
+	}{
+		{first, nil},
+		{second, queue.ErrFull},
+		// an id already queued is pushed again.
+		{first, nil},
+	}
+	for _, tt := range tests {
+		if err := pq.PushMax(tt.id, 1); err != tt.err {
+			t.Errorf("PushMax(%v, 1) = %v, want %v", tt.id, err, tt.err)
+		}
+	}
+	if n, err := pq.Len(); err != nil || n != 1 {
+		t.Errorf("Len() = %d, %v, want 1", n, err)
+	}
+}
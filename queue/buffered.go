@@ -0,0 +1,275 @@
+package queue
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid"
+)
+
+// ErrBufferFull is returned by Buffered.Push when the queue is
+// unavailable and its buffer is full.
+var ErrBufferFull = errors.New("queue unavailable and its buffer is full")
+
+var _ Queue = (*Buffered)(nil)
+
+// Buffered is a Queue that keeps the pushed ids in memory while the
+// underlying queue is unavailable, and pushes them with an exponential
+// backoff until it recovers, so an outage of redis does not lose the
+// messages put meanwhile. The buffered ids are lost if the service stops
+// before the queue recovers.
+type Buffered struct {
+	Queue
+
+	// Max is the maximum number of buffered ids.
+	Max int
+
+	// MinBackoff and MaxBackoff bound the delay between two attempts to
+	// push the buffered ids.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	mu     sync.Mutex
+	buffer []pending
+	err    error
+	// n is the length of the queue, read by Len and kept by the pushes,
+	// pops and deletes made through q.
+	n int
+}
+
+// pending is a buffered id with the time it is due.
+type pending struct {
+	id  ulid.ULID
+	due time.Time
+}
+
+// NewBuffered returns q buffering up to max ids.
+func NewBuffered(q Queue, max int) *Buffered {
+	return &Buffered{
+		Queue:      q,
+		Max:        max,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// For returns the buffered queue of tenant.
+func (q *Buffered) For(tenant string) (Queue, error) {
+	tq, err := q.Queue.For(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewBuffered(tq, q.Max)
+	b.MinBackoff, b.MaxBackoff = q.MinBackoff, q.MaxBackoff
+
+	return b, nil
+}
+
+// Push pushes id to the queue or, if it is unavailable, to the buffer.
+// While there are buffered ids the new ones are buffered too, so they are
+// not delayed by the timeouts of the queue.
+func (q *Buffered) Push(id ulid.ULID) error {
+	return q.PushAt(id, ulid.Time(id.Time()))
+}
+
+// PushAt pushes id due at t as Push does.
+func (q *Buffered) PushAt(id ulid.ULID, t time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.buffer) == 0 {
+		err := q.Queue.PushAt(id, t)
+		if err == nil {
+			q.n++
+			return nil
+		}
+
+		slog.Warn("priority queue unavailable, buffering pushes", "error", err)
+		q.err = err
+		go q.flush()
+	}
+
+	if len(q.buffer) >= q.Max {
+		return ErrBufferFull
+	}
+	q.buffer = append(q.buffer, pending{id, t})
+
+	return nil
+}
+
+// PushMax pushes id to the queue unless it holds max ids, counting the
+// buffered ones. While the queue is unavailable the last length known is
+// checked, as Len does, and id is buffered.
+func (q *Buffered) PushMax(id ulid.ULID, max int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.buffer) == 0 {
+		err := q.Queue.PushMax(id, max)
+		if err == nil {
+			q.n++
+			return nil
+		}
+		if errors.Is(err, ErrFull) {
+			return err
+		}
+
+		slog.Warn("priority queue unavailable, buffering pushes", "error", err)
+		q.err = err
+		go q.flush()
+	}
+
+	for _, p := range q.buffer {
+		if p.id == id {
+			return nil
+		}
+	}
+	if q.n+len(q.buffer) >= max {
+		return ErrFull
+	}
+	if len(q.buffer) >= q.Max {
+		return ErrBufferFull
+	}
+	q.buffer = append(q.buffer, pending{id, ulid.Time(id.Time())})
+
+	return nil
+}
+
+// Pop ...
+func (q *Buffered) Pop() (*ulid.ULID, error) {
+	id, err := q.Queue.Pop()
+	if err != nil || id == nil {
+		return id, err
+	}
+
+	q.mu.Lock()
+	if q.n > 0 {
+		q.n--
+	}
+	q.mu.Unlock()
+
+	return id, nil
+}
+
+// Delete removes id from the buffer or the queue.
+func (q *Buffered) Delete(id ulid.ULID) (bool, error) {
+	q.mu.Lock()
+	for i := range q.buffer {
+		if q.buffer[i].id == id {
+			q.buffer = append(q.buffer[:i], q.buffer[i+1:]...)
+			q.mu.Unlock()
+			return true, nil
+		}
+	}
+	q.mu.Unlock()
+
+	found, err := q.Queue.Delete(id)
+	if found {
+		q.mu.Lock()
+		if q.n > 0 {
+			q.n--
+		}
+		q.mu.Unlock()
+	}
+
+	return found, err
+}
+
+// Len includes the buffered ids. While the queue is unavailable it
+// returns the last length known, which cannot change meanwhile, so the
+// quotas keep working during an outage.
+func (q *Buffered) Len() (int, error) {
+	n, err := q.Queue.Len()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err != nil {
+		n = q.n
+	} else {
+		q.n = n
+	}
+
+	return n + len(q.buffer), nil
+}
+
+// List includes the buffered ids, after the ones of the queue.
+func (q *Buffered) List() ([]ulid.ULID, error) {
+	ids, err := q.Queue.List()
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, p := range q.buffer {
+		ids = append(ids, p.id)
+	}
+
+	return ids, nil
+}
+
+// Buffered returns the number of buffered ids and the last error of the
+// queue, which is nil once they were pushed.
+func (q *Buffered) Buffered() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.buffer), q.err
+}
+
+// flush pushes the buffered ids until the buffer is empty, it runs in
+// its goroutine.
+func (q *Buffered) flush() {
+	delay := q.MinBackoff
+	for {
+		time.Sleep(delay)
+
+		n, err := q.drain()
+		if err == nil {
+			slog.Info("priority queue recovered", "flushed", n)
+			return
+		}
+
+		delay *= 2
+		if delay > q.MaxBackoff {
+			delay = q.MaxBackoff
+		}
+	}
+}
+
+// drain pushes the buffered ids, in order, until one fails. The lock is
+// not held while pushing, so the timeouts of the queue do not block the
+// new pushes.
+func (q *Buffered) drain() (int, error) {
+	var n int
+	for {
+		q.mu.Lock()
+		if len(q.buffer) == 0 {
+			q.err = nil
+			q.mu.Unlock()
+			return n, nil
+		}
+		p := q.buffer[0]
+		q.mu.Unlock()
+
+		err := q.Queue.PushAt(p.id, p.due)
+
+		q.mu.Lock()
+		if err != nil {
+			q.err = err
+			q.mu.Unlock()
+			return n, err
+		}
+		if len(q.buffer) > 0 && q.buffer[0] == p {
+			q.buffer = q.buffer[1:]
+		}
+		q.n++
+		q.mu.Unlock()
+		n++
+	}
+}
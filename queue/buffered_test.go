@@ -0,0 +1,268 @@
+package queue
+
+import (
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid"
+)
+
+var errDown = errors.New("queue down")
+
+// fakeQueue is an in memory Queue that fails every call while down.
+type fakeQueue struct {
+	mu   sync.Mutex
+	down bool
+	due  map[ulid.ULID]time.Time
+}
+
+func newFakeQueue() *fakeQueue {
+	return &fakeQueue{due: make(map[ulid.ULID]time.Time)}
+}
+
+func (q *fakeQueue) setDown(down bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.down = down
+}
+
+func (q *fakeQueue) Push(id ulid.ULID) error {
+	return q.PushAt(id, ulid.Time(id.Time()))
+}
+
+func (q *fakeQueue) PushAt(id ulid.ULID, t time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.down {
+		return errDown
+	}
+	q.due[id] = t
+	return nil
+}
+
+func (q *fakeQueue) PushMax(id ulid.ULID, max int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.down {
+		return errDown
+	}
+	if _, ok := q.due[id]; !ok && len(q.due) >= max {
+		return ErrFull
+	}
+	q.due[id] = ulid.Time(id.Time())
+	return nil
+}
+
+func (q *fakeQueue) Peek() (*ulid.ULID, time.Time, error) {
+	ids, err := q.List()
+	if err != nil || len(ids) == 0 {
+		return nil, time.Time{}, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return &ids[0], q.due[ids[0]], nil
+}
+
+func (q *fakeQueue) Pop() (*ulid.ULID, error) {
+	id, _, err := q.Peek()
+	if err != nil || id == nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.due, *id)
+	return id, nil
+}
+
+func (q *fakeQueue) Delete(id ulid.ULID) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.down {
+		return false, errDown
+	}
+	_, ok := q.due[id]
+	delete(q.due, id)
+	return ok, nil
+}
+
+func (q *fakeQueue) Len() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.down {
+		return 0, errDown
+	}
+	return len(q.due), nil
+}
+
+func (q *fakeQueue) List() ([]ulid.ULID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.down {
+		return nil, errDown
+	}
+
+	ids := make([]ulid.ULID, 0, len(q.due))
+	for id := range q.due {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if !q.due[ids[i]].Equal(q.due[ids[j]]) {
+			return q.due[ids[i]].Before(q.due[ids[j]])
+		}
+		return ids[i].Compare(ids[j]) < 0
+	})
+	return ids, nil
+}
+
+func (q *fakeQueue) For(string) (Queue, error) { return q, nil }
+
+func (q *fakeQueue) Ping() error {
+	_, err := q.Len()
+	return err
+}
+
+func newID(t *testing.T, at time.Time) ulid.ULID {
+	t.Helper()
+	return ulid.MustNew(ulid.Timestamp(at), rand.Reader)
+}
+
+func TestBuffered(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	queued := newID(t, now)
+	first := newID(t, now.Add(time.Second))
+	retried := newID(t, now.Add(2*time.Second))
+	full := newID(t, now.Add(3*time.Second))
+
+	fq := newFakeQueue()
+	q := NewBuffered(fq, 2)
+	q.MinBackoff, q.MaxBackoff = time.Millisecond, time.Millisecond
+
+	if err := q.Push(queued); err != nil {
+		t.Fatal(err)
+	}
+	fq.setDown(true)
+
+	steps := []struct {
+		name string
+		push func() error
+		want error
+		len  int
+	}{
+		{"push while down", func() error { return q.Push(first) }, nil, 2},
+		{"push at while down", func() error { return q.PushAt(retried, now.Add(time.Minute)) }, nil, 3},
+		{"buffer full", func() error { return q.Push(full) }, ErrBufferFull, 3},
+	}
+	for _, s := range steps {
+		if err := s.push(); err != s.want {
+			t.Errorf("%s: push = %v, want %v", s.name, err, s.want)
+		}
+
+		// the length of the queue is the last one read before the outage.
+		n, err := q.Len()
+		if err != nil || n != s.len {
+			t.Errorf("%s: Len() = %d, %v, want %d", s.name, n, err, s.len)
+		}
+	}
+
+	if n, err := q.Buffered(); n != 2 || err != errDown {
+		t.Errorf("Buffered() = %d, %v, want 2, %v", n, err, errDown)
+	}
+
+	fq.setDown(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if n, err := q.Buffered(); n == 0 && err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("buffer not flushed after the queue recovered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ids, err := fq.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ulid.ULID{queued, first, retried}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("queue = %v, want %v", ids, want)
+	}
+	if due := fq.due[retried]; !due.Equal(now.Add(time.Minute)) {
+		t.Errorf("retried due at %v, want %v", due, now.Add(time.Minute))
+	}
+}
+
+func TestBufferedDelete(t *testing.T) {
+	now := time.Now()
+	queued := newID(t, now)
+	buffered := newID(t, now.Add(time.Second))
+
+	fq := newFakeQueue()
+	q := NewBuffered(fq, 10)
+	q.MinBackoff, q.MaxBackoff = time.Hour, time.Hour
+
+	if err := q.Push(queued); err != nil {
+		t.Fatal(err)
+	}
+	fq.setDown(true)
+	if err := q.Push(buffered); err != nil {
+		t.Fatal(err)
+	}
+
+	if found, err := q.Delete(buffered); !found || err != nil {
+		t.Errorf("Delete(buffered) = %v, %v, want true", found, err)
+	}
+	if _, err := q.Delete(queued); err != errDown {
+		t.Errorf("Delete(queued) = %v, want %v while down", err, errDown)
+	}
+
+	fq.setDown(false)
+	if found, err := q.Delete(queued); !found || err != nil {
+		t.Errorf("Delete(queued) = %v, %v, want true", found, err)
+	}
+	ids, err := q.List()
+	if err != nil || len(ids) != 0 {
+		t.Errorf("List() = %v, %v, want an empty queue", ids, err)
+	}
+}
+
+func TestBufferedPushMax(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	queued := newID(t, now)
+	buffered := newID(t, now.Add(time.Second))
+	over := newID(t, now.Add(2*time.Second))
+
+	fq := newFakeQueue()
+	q := NewBuffered(fq, 10)
+	q.MinBackoff, q.MaxBackoff = time.Hour, time.Hour
+
+	steps := []struct {
+		name string
+		id   ulid.ULID
+		down bool
+		want error
+	}{
+		{"push", queued, false, nil},
+		{"push while down", buffered, true, nil},
+		// the buffered ids count towards the maximum.
+		{"over the maximum while down", over, true, ErrFull},
+		{"push again while down", buffered, true, nil},
+	}
+	for _, s := range steps {
+		fq.setDown(s.down)
+		if err := q.PushMax(s.id, 2); err != s.want {
+			t.Errorf("%s: PushMax() = %v, want %v", s.name, err, s.want)
+		}
+	}
+
+	if n, _ := q.Buffered(); n != 1 {
+		t.Errorf("Buffered() = %d, want 1", n)
+	}
+}
@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid"
+)
+
+// Drivers ...
+const (
+	// Redis ...
+	Redis = "redis"
+	// Bolt ...
+	Bolt = "bolt"
+)
+
+// ErrFull is returned by PushMax when the queue already holds the maximum
+// number of ids.
+var ErrFull = errors.New("queue full")
+
+// Queue keeps the ids of the messages waiting to be delivered ordered by
+// the time encoded in their ULID, or the one given to PushAt.
+type Queue interface {
+	// Push adds the id to the queue, due at the time encoded in it.
+	Push(id ulid.ULID) error
+
+	// PushAt adds the id to the queue due at t instead of the time
+	// encoded in it, as the retries of the failed deliveries.
+	PushAt(id ulid.ULID, t time.Time) error
+
+	// PushMax adds the id to the queue as Push does, unless the queue
+	// already holds max ids without it, in which case it fails with
+	// ErrFull. The length is checked and the id pushed atomically.
+	PushMax(id ulid.ULID, max int) error
+
+	// Peek returns the id with the earliest time and the time it is due
+	// without removing it.
+	//
+	// In case the queue is empty the id will be nil.
+	Peek() (*ulid.ULID, time.Time, error)
+
+	// Pop removes and returns the id with the earliest time.
+	//
+	// In case the queue is empty the id will be nil.
+	Pop() (*ulid.ULID, error)
+
+	// Delete removes the id from the queue, reporting whether it was found.
+	Delete(id ulid.ULID) (bool, error)
+
+	// Len returns the number of ids in the queue.
+	Len() (int, error)
+
+	// List returns every id in the queue, from the earliest time.
+	List() ([]ulid.ULID, error)
+
+	// For returns the queue of the given tenant, kept apart from the
+	// queues of the other tenants.
+	For(tenant string) (Queue, error)
+
+	// Ping reports whether the storage of the queue is reachable.
+	Ping() error
+}
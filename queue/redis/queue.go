@@ -0,0 +1,314 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/oklog/ulid"
+)
+
+var (
+	scripts map[string]*redis.Script
+
+	scriptsSources = map[string]string{
+		"pop": `
+			local result_set = redis.call('ZRANGE', KEYS[1], 0, 0)
+			if not result_set or #result_set == 0 then
+				return ''
+			end
+
+			redis.call('ZREMRANGEBYRANK', KEYS[1], 0, 0)
+
+			return result_set[1]
+		`,
+		"push": `
+			local timestamp = ARGV[1]
+			local id = ARGV[2]
+
+			redis.call('ZADD', KEYS[1], timestamp, id)
+
+			return true
+		`,
+		"pushmax": `
+			local timestamp = ARGV[1]
+			local id = ARGV[2]
+			local max = tonumber(ARGV[3])
+
+			if not redis.call('ZSCORE', KEYS[1], id) and redis.call('ZCARD', KEYS[1]) >= max then
+				return 0
+			end
+
+			redis.call('ZADD', KEYS[1], timestamp, id)
+
+			return 1
+		`,
+		"peek": `
+			local result_set = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+			if not result_set or #result_set == 0 then
+				return false
+			end
+			return result_set
+		`,
+		"delete": `
+			local id = ARGV[1]
+
+			local result_set = redis.call('ZREM', KEYS[1], id)
+
+			return result_set
+		`,
+		"len": `
+			return redis.call('ZCARD', KEYS[1])
+		`,
+		"list": `
+			return redis.call('ZRANGE', KEYS[1], 0, -1)
+		`,
+	}
+)
+
+func init() {
+	scripts = make(map[string]*redis.Script)
+
+	for k, v := range scriptsSources {
+		scripts[k] = redis.NewScript(1, v)
+	}
+}
+
+var _ queue.Queue = (*Queue)(nil)
+
+// Key is the sorted set of the default tenant, the other tenants
+// prefix it with their id.
+const Key = "pq:ids"
+
+// Queue is a priority queue backed by a redis sorted set.
+type Queue struct {
+	pool interface {
+		Get() redis.Conn
+	}
+	key string
+}
+
+// PoolConfig sizes the pool of redis connections, zero values use the
+// defaults.
+type PoolConfig struct {
+	MaxIdle     int           `yaml:"max_idle" toml:"max_idle" json:"max_idle"`
+	MaxActive   int           `yaml:"max_active" toml:"max_active" json:"max_active"`
+	IdleTimeout time.Duration `yaml:"idle_timeout" toml:"idle_timeout" json:"idle_timeout"`
+}
+
+// NewQueue ...
+func NewQueue(url string, pc PoolConfig) (*Queue, error) {
+	if pc.MaxIdle == 0 {
+		pc.MaxIdle = 10
+	}
+	if pc.IdleTimeout == 0 {
+		pc.IdleTimeout = 5 * time.Second
+	}
+
+	// the pool dials again on the next Get after a connection fails, and
+	// the idle connections are tested, so the queue recovers by itself
+	// once redis is back.
+	pool := &redis.Pool{
+		Dial:         dial(url),
+		TestOnBorrow: testOnBorrow,
+		MaxIdle:      pc.MaxIdle,
+		MaxActive:    pc.MaxActive,
+		IdleTimeout:  pc.IdleTimeout,
+	}
+
+	conn := pool.Get()
+	if err := conn.Err(); err != nil {
+		return nil, err
+	}
+	conn.Close()
+
+	return &Queue{pool, Key}, nil
+}
+
+// For returns the queue of tenant, sharing the connections of pq.
+func (pq *Queue) For(tenantID string) (queue.Queue, error) {
+	return &Queue{pq.pool, tenant.Key(tenantID, Key)}, nil
+}
+
+// Push ...
+func (pq *Queue) Push(id ulid.ULID) error {
+	return pq.push(id, id.Time())
+}
+
+// PushAt scores id with t instead of its time.
+func (pq *Queue) PushAt(id ulid.ULID, t time.Time) error {
+	return pq.push(id, ulid.Timestamp(t))
+}
+
+func (pq *Queue) push(id ulid.ULID, ms uint64) error {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	_, err := scripts["push"].Do(conn, pq.key, ms, id.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PushMax checks the length of the sorted set and adds id on the same
+// script.
+func (pq *Queue) PushMax(id ulid.ULID, max int) error {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	ok, err := redis.Bool(scripts["pushmax"].Do(conn, pq.key, id.Time(), id.String(), max))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return queue.ErrFull
+	}
+
+	return nil
+}
+
+// Peek returns the id with the lowest score, which is the time it is due.
+func (pq *Queue) Peek() (*ulid.ULID, time.Time, error) {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Strings(scripts["peek"].Do(conn, pq.key))
+	if err != nil {
+		if err == redis.ErrNil {
+			return nil, time.Time{}, nil
+		}
+		return nil, time.Time{}, err
+	}
+	if len(values) != 2 {
+		return nil, time.Time{}, fmt.Errorf("unexpected peek reply %q", values)
+	}
+
+	id, err := ulid.Parse(values[0])
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	ms, err := strconv.ParseUint(values[1], 10, 64)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &id, ulid.Time(ms), nil
+}
+
+// Pop ...
+func (pq *Queue) Pop() (*ulid.ULID, error) {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	idStr, err := redis.String(scripts["pop"].Do(conn, pq.key))
+	if err != nil {
+		return nil, err
+	}
+
+	if idStr == "" {
+		return nil, nil
+	}
+
+	id, err := ulid.Parse(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// Delete ...
+func (pq *Queue) Delete(id ulid.ULID) (bool, error) {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	res, err := redis.Int(scripts["delete"].Do(conn, pq.key, id.String()))
+	if err != nil {
+		return false, err
+	}
+
+	if res == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Len ...
+func (pq *Queue) Len() (int, error) {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	n, err := redis.Int(scripts["len"].Do(conn, pq.key))
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// List ...
+func (pq *Queue) List() ([]ulid.ULID, error) {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	idsStr, err := redis.Strings(scripts["list"].Do(conn, pq.key))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]ulid.ULID, 0, len(idsStr))
+	for _, idStr := range idsStr {
+		id, err := ulid.Parse(idStr)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Ping ...
+func (pq *Queue) Ping() error {
+	conn := pq.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	return err
+}
+
+// timeout bounds the connection and the operations on redis, so an
+// unavailable redis fails the operations instead of blocking them.
+const timeout = 5 * time.Second
+
+func dial(url string) func() (redis.Conn, error) {
+	return func() (redis.Conn, error) {
+		conn, err := redis.DialURL(url,
+			redis.DialConnectTimeout(timeout),
+			redis.DialReadTimeout(timeout),
+			redis.DialWriteTimeout(timeout),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	}
+}
+
+// testOnBorrow pings the connections idle for more than a second before
+// using them.
+func testOnBorrow(c redis.Conn, t time.Time) error {
+	if time.Since(t) < time.Second {
+		return nil
+	}
+
+	_, err := c.Do("PING")
+	return err
+}
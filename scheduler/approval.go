@@ -0,0 +1,358 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/microapis/messages-core/backend"
+	"github.com/microapis/messages-core/channel"
+	"github.com/microapis/messages-core/content"
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/store"
+	"github.com/microapis/messages-core/tracing"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+)
+
+// Approval modes of the messages received by Put.
+const (
+	// ApprovalInline approves the message before Put returns, which fails
+	// with ErrNotApproved when the content is rejected.
+	ApprovalInline = "inline"
+	// ApprovalAsync approves the message after Put returns, the outcome is
+	// found on its status and history.
+	ApprovalAsync = "async"
+)
+
+// rejection is the error of a content that was not approved, by the
+// schema of the channel or by its backend, with the reason if any. err
+// is the cause of a template that could not be rendered.
+type rejection struct {
+	reason string
+	err    error
+}
+
+func (e *rejection) Error() string {
+	if e.reason == "" {
+		return ErrNotApproved.Error()
+	}
+
+	return ErrNotApproved.Error() + ": " + e.reason
+}
+
+func (e *rejection) Unwrap() []error {
+	if e.err == nil {
+		return []error{ErrNotApproved}
+	}
+
+	return []error{e.err, ErrNotApproved}
+}
+
+// approveMessage approves the stored message m, persists the outcome and,
+// when approved, enqueues it. The reason of a rejection is kept on the
+// error of the transition.
+func (s *service) approveMessage(ctx context.Context, m *message.Message, source string) (err error) {
+	ctx, span := tracing.Start(ctx, "scheduler.approve",
+		tracing.Message(m.ID.String(), m.Channel, m.Provider),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if m.Render {
+		err = s.render(m)
+	}
+	if err == nil {
+		err = s.checkContent(ctx, m, m.Content)
+	}
+
+	// the approvals of Put are requested by the client that put the
+	// message, the ones resumed by the scheduler by nobody.
+	var client string
+	if source == message.SourceAPI {
+		client = m.Client
+	}
+
+	var r *rejection
+	switch {
+	case errors.As(err, &r):
+		if e := s.updateStatus(m, message.Transition{
+			To:     message.FailedApprove,
+			Source: source,
+			Error:  r.reason,
+			Client: client,
+		}); e != nil {
+			return e
+		}
+
+		return err
+	case err != nil:
+		if e := s.updateStatus(m, message.Transition{
+			To:     message.CrashedApprove,
+			Source: source,
+			Error:  err.Error(),
+			Client: client,
+		}); e != nil {
+			return e
+		}
+
+		// TODO(ca): send callback when could not updated status
+		return err
+	}
+
+	if err := s.updateStatus(m, message.Transition{
+		To:     message.Approved,
+		Source: source,
+		Client: client,
+	}); err != nil {
+		return err
+	}
+
+	// only the messages put are limited by the quota, the ones resumed
+	// were already accepted.
+	var max int
+	if source == message.SourceAPI {
+		max = s.maxQueued
+	}
+
+	_, espan := tracing.Start(ctx, "scheduler.enqueue")
+	err = s.enqueue(m.ID, max)
+	tracing.End(espan, err)
+	if err != nil {
+		// the message is cancelled, so the client can put it again
+		// without sending it twice.
+		if e := s.updateStatus(m, message.Transition{
+			To:     message.Cancelled,
+			Source: message.SourceScheduler,
+			Error:  err.Error(),
+		}); e != nil {
+			s.log.Error("could not cancel message not enqueued", "id", m.ID, "error", e)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// render renders the template of the message m with the variables held on
+// its content, and stores the rendered content. It fails with a *rejection
+// when the template is not found or the variables cannot render it.
+func (s *service) render(m *message.Message) error {
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(m.Content), &vars); err != nil {
+		err = &invalidArgument{"variables", err}
+		return &rejection{err.Error(), err}
+	}
+
+	t, value, err := s.RenderTemplate(m.Channel, m.Template, m.Locale, m.TemplateVersion, vars)
+	var ia *invalidArgument
+	switch {
+	case errors.As(err, &ia), errors.Is(err, store.ErrNotFound):
+		return &rejection{err.Error(), err}
+	case err != nil:
+		return err
+	}
+
+	if err := s.ms.Render(m.ID, value, t.ContentType, t.Version, t.Locale); err != nil {
+		return err
+	}
+	m.Content = value
+	m.ContentType = t.ContentType
+	m.TemplateVersion = t.Version
+	m.Locale = t.Locale
+	m.Render = false
+
+	return nil
+}
+
+// approveUpdate approves value, the new content of the message m,
+// without changing the stored message.
+func (s *service) approveUpdate(ctx context.Context, m *message.Message, value string) (err error) {
+	ctx, span := tracing.Start(ctx, "scheduler.approve",
+		tracing.Message(m.ID.String(), m.Channel, m.Provider),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return s.checkContent(ctx, m, value)
+}
+
+// checkContent validates value, the content of the message m, against
+// the schema of its channel and approves it with the backend. It fails
+// with a *rejection when the content is not approved.
+func (s *service) checkContent(ctx context.Context, m *message.Message, value string) error {
+	if err := s.validateContent(m.Channel, m.ContentType, value); err != nil {
+		return err
+	}
+
+	ok, err := s.approveContent(ctx, m.Channel, value)
+
+	var rejected *backend.RejectedError
+	switch {
+	case errors.As(err, &rejected):
+		return &rejection{reason: rejected.Reason}
+	case err != nil:
+		return err
+	case !ok:
+		return &rejection{}
+	}
+
+	return nil
+}
+
+// validateContent validates value, of the given content type, against
+// the schema of the channel, if any. The content is not validated when
+// the channels are not configured.
+func (s *service) validateContent(name, contentType, value string) error {
+	if s.cs == nil {
+		return nil
+	}
+
+	c, err := s.channel(name)
+	if err != nil {
+		return err
+	}
+	if c.Schema == nil {
+		return nil
+	}
+
+	v, err := s.validator(name, c.Schema)
+	if err != nil {
+		return fmt.Errorf("channel %s: schema: %v", name, err)
+	}
+
+	if err := v.Validate(contentType, value); err != nil {
+		var invalid *content.InvalidError
+		if errors.As(err, &invalid) {
+			return &rejection{reason: invalid.Reason}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validator returns the compiled schema sc of the channel name, which is
+// compiled again only when the schema changes.
+func (s *service) validator(name string, sc *channel.Schema) (*content.Validator, error) {
+	h := sha256.New()
+	for _, b := range [][]byte{sc.JSON, sc.Descriptor, []byte(sc.Message)} {
+		fmt.Fprintf(h, "%d:", len(b))
+		h.Write(b)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+
+	s.validatorsMu.Lock()
+	cv, ok := s.validators[name]
+	s.validatorsMu.Unlock()
+	if ok && cv.sum == sum {
+		return cv.v, nil
+	}
+
+	v, err := content.Compile(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	s.validatorsMu.Lock()
+	s.validators[name] = &compiled{sum, v}
+	s.validatorsMu.Unlock()
+
+	return v, nil
+}
+
+// compiled is a validator with the sum of the schema it was compiled
+// from.
+type compiled struct {
+	sum [sha256.Size]byte
+	v   *content.Validator
+}
+
+// claim marks the approval of id as in flight, it returns false if it
+// already is.
+func (s *service) claim(id ulid.ULID) bool {
+	s.approvingMu.Lock()
+	defer s.approvingMu.Unlock()
+
+	if s.approving[id] {
+		return false
+	}
+	s.approving[id] = true
+
+	return true
+}
+
+// release ends the approval of id claimed by claim.
+func (s *service) release(id ulid.ULID) {
+	s.approvingMu.Lock()
+	defer s.approvingMu.Unlock()
+
+	delete(s.approving, id)
+}
+
+// resume runs the approval of the messages received before a restart,
+// and enqueues the approved ones that did not reach the queue. It runs in
+// its goroutine when the scheduler starts, while Put is served, so the
+// messages with an approval in flight are skipped, and every message is
+// read again once claimed, as its approval may have ended meanwhile.
+func (s *service) resume() {
+	queued := make(map[ulid.ULID]bool)
+	ids, err := s.pq.List()
+	if err != nil {
+		s.log.Error("could not list priority queue", "error", err)
+		return
+	}
+	for _, id := range ids {
+		queued[id] = true
+	}
+
+	var received, approved []*message.Message
+	err = s.ms.ForEach(false, func(m *message.Message, _ []*message.Transition) error {
+		switch {
+		case message.IsReceived(m.Status):
+			received = append(received, m)
+		case message.IsApproved(m.Status) && !queued[m.ID]:
+			approved = append(approved, m)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.log.Error("could not list received messages", "error", err)
+		return
+	}
+
+	for _, m := range approved {
+		s.resumeMessage(m.ID, message.IsApproved, func(m *message.Message) error {
+			return s.enqueue(m.ID, 0)
+		})
+	}
+	for _, m := range received {
+		s.resumeMessage(m.ID, message.IsReceived, func(m *message.Message) error {
+			ctx := tracing.Extract(context.Background(), m.TraceContext)
+			return s.approveMessage(ctx, m, message.SourceScheduler)
+		})
+	}
+}
+
+// resumeMessage claims the approval of id and runs fn with the message if
+// its status is still one of the given ones.
+func (s *service) resumeMessage(id ulid.ULID, is func(status string) bool, fn func(m *message.Message) error) {
+	if !s.claim(id) {
+		return
+	}
+	defer s.release(id)
+
+	m, err := s.ms.Get(id)
+	if err != nil {
+		s.log.Error("could not resume message", "id", id, "error", err)
+		return
+	}
+	if !is(m.Status) {
+		return
+	}
+
+	if err := fn(m); err != nil {
+		s.log.Warn("message not resumed", "id", id, "status", m.Status, "error", err)
+	}
+}
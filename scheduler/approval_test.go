@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/store"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+)
+
+// queued reports whether id is on the queue of s.
+func queued(t *testing.T, s *service, id ulid.ULID) bool {
+	t.Helper()
+	ids, err := s.pq.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, qid := range ids {
+		if qid == id {
+			return true
+		}
+	}
+	return false
+}
+
+func statusOf(t *testing.T, s *service, id ulid.ULID) string {
+	t.Helper()
+	m, err := s.ms.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.Status
+}
+
+func TestPutApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		approve func(string) (bool, error)
+		err     error
+		status  string
+		queued  bool
+	}{
+		{"approved", func(string) (bool, error) { return true, nil }, nil, message.Approved, true},
+		{"rejected", func(string) (bool, error) { return false, nil }, ErrNotApproved, message.FailedApprove, false},
+		{"crashed", func(string) (bool, error) { return false, errors.New("backend down") }, nil, message.CrashedApprove, false},
+	}
+
+	for _, tt := range tests {
+		for _, async := range []bool{false, true} {
+			s := newService(t)
+			s.approve, s.async = tt.approve, async
+
+			m := newMessage("email", "content")
+			err := s.Put(m)
+			switch {
+			case async && err != nil:
+				t.Errorf("%s: async Put() = %v, want nil", tt.name, err)
+			case !async && tt.err != nil && !errors.Is(err, tt.err):
+				t.Errorf("%s: Put() = %v, want %v", tt.name, err, tt.err)
+			case !async && tt.status == message.Approved && err != nil:
+				t.Errorf("%s: Put() = %v, want nil", tt.name, err)
+			}
+
+			// the asynchronous approval ends after Put.
+			deadline := time.Now().Add(2 * time.Second)
+			for async && statusOf(t, s, m.ID) == message.Received && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := statusOf(t, s, m.ID); got != tt.status {
+				t.Errorf("%s: status with async %v = %s, want %s", tt.name, async, got, tt.status)
+			}
+			if got := queued(t, s, m.ID); got != tt.queued {
+				t.Errorf("%s: queued with async %v = %v, want %v", tt.name, async, got, tt.queued)
+			}
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	s := newService(t)
+
+	add := func(transitions ...string) ulid.ULID {
+		t.Helper()
+		m := newMessage("email", "content")
+		m.Status = message.Received
+		if err := s.ms.AddMessage(m, message.SourceAPI); err != nil {
+			t.Fatal(err)
+		}
+		for _, to := range transitions {
+			if err := s.ms.UpdateStatus(m.ID, message.Transition{To: to, Source: message.SourceScheduler}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return m.ID
+	}
+
+	received := add()
+	approved := add(message.Approved)
+	sent := add(message.Approved, message.Sending, message.Sent)
+	inFlight := add()
+	if !s.claim(inFlight) {
+		t.Fatal("claim() = false, want true")
+	}
+
+	s.resume()
+
+	tests := []struct {
+		name   string
+		id     ulid.ULID
+		status string
+		queued bool
+	}{
+		{"received", received, message.Approved, true},
+		{"approved", approved, message.Approved, true},
+		{"sent", sent, message.Sent, false},
+		// the approval in flight is left to its owner.
+		{"in flight", inFlight, message.Received, false},
+	}
+	for _, tt := range tests {
+		if got := statusOf(t, s, tt.id); got != tt.status {
+			t.Errorf("%s: status after resume = %s, want %s", tt.name, got, tt.status)
+		}
+		if got := queued(t, s, tt.id); got != tt.queued {
+			t.Errorf("%s: queued after resume = %v, want %v", tt.name, got, tt.queued)
+		}
+	}
+
+	// the claims of resume are released.
+	for _, id := range []ulid.ULID{received, approved} {
+		if !s.claim(id) {
+			t.Errorf("claim(%v) after resume = false, want true", id)
+		}
+	}
+}
+
+func TestUpdateApproval(t *testing.T) {
+	s := newService(t)
+	s.approve = func(content string) (bool, error) {
+		switch content {
+		case "rejected":
+			return false, nil
+		case "crashed":
+			return false, errors.New("backend down")
+		}
+		return true, nil
+	}
+
+	m := newMessage("email", "v1")
+	if err := s.Put(m); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		content string
+		rev     int32
+		fails   bool
+		err     error
+		want    string
+		wantRev int32
+	}{
+		{"rejected", 1, true, ErrNotApproved, "v1", 1},
+		{"v2", 1, false, nil, "v2", 2},
+		{"crashed", 2, true, nil, "v2", 2},
+		{"v3", 1, true, store.ErrConflict, "v2", 2},
+		{"v3", 2, false, nil, "v3", 3},
+	}
+
+	for _, tt := range tests {
+		err := s.Update(m.ID, tt.content, tt.rev, "client")
+		if (err != nil) != tt.fails || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("Update(%q) at revision %d = %v, want fails %v with %v", tt.content, tt.rev, err, tt.fails, tt.err)
+		}
+
+		got, err := s.Get(m.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Content != tt.want || got.Revision != tt.wantRev {
+			t.Errorf("after Update(%q): %q at revision %d, want %q at revision %d", tt.content, got.Content, got.Revision, tt.want, tt.wantRev)
+		}
+		if got.Status != message.Approved {
+			t.Errorf("after Update(%q): status %s, want %s", tt.content, got.Status, message.Approved)
+		}
+	}
+
+	versions, err := s.GetVersions(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Content != "v1" || versions[1].Content != "v2" {
+		t.Errorf("GetVersions() = %v, want v1 and v2", versions)
+	}
+}
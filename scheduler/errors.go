@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/microapis/messages-core/message"
+	pb "github.com/microapis/messages-core/proto"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/store"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is the domain of the ErrorInfo details.
+const errorDomain = "messages.microapis.github.com"
+
+var (
+	// ErrNotApproved is returned by Put and Update when the backend
+	// rejects the content of the message.
+	ErrNotApproved = errors.New("message content not approved")
+
+	// ErrNotConfigured is returned when a feature needs a store or a
+	// backend that is not configured.
+	ErrNotConfigured = errors.New("not configured")
+)
+
+// invalidArgument is a request with an invalid field.
+type invalidArgument struct {
+	field string
+	err   error
+}
+
+func (e *invalidArgument) Error() string {
+	return e.field + ": " + e.err.Error()
+}
+
+// retryDelay is the delay suggested to the clients on the retryable
+// errors.
+var retryDelay = durationpb.New(time.Second)
+
+// toStatus returns the gRPC status of err, with the code of its kind and
+// the details describing it. The status errors, as the ones of the
+// authentication, are returned as they are.
+func toStatus(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+
+	var (
+		code    = codes.Internal
+		reason  = "INTERNAL"
+		details []protoadapt.MessageV1
+	)
+
+	var ia *invalidArgument
+	var se *message.StatusError
+	var ne net.Error
+	switch {
+	case errors.As(err, &ia):
+		code, reason = codes.InvalidArgument, "INVALID_ARGUMENT"
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       ia.field,
+				Description: ia.err.Error(),
+			}},
+		})
+	case errors.Is(err, store.ErrNotFound):
+		code, reason = codes.NotFound, "NOT_FOUND"
+	case errors.Is(err, store.ErrConflict):
+		code, reason = codes.Aborted, "CONFLICT"
+	case errors.Is(err, ErrNotApproved):
+		code, reason = codes.FailedPrecondition, "NOT_APPROVED"
+	case errors.As(err, &se):
+		code, reason = codes.FailedPrecondition, "INVALID_STATUS"
+		details = append(details, &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        "STATUS",
+				Subject:     se.Status,
+				Description: se.Error(),
+			}},
+		})
+	case errors.Is(err, ErrNotConfigured):
+		code, reason = codes.FailedPrecondition, "NOT_CONFIGURED"
+	case errors.Is(err, ErrQuotaExceeded):
+		code, reason = codes.ResourceExhausted, "QUOTA_EXCEEDED"
+		details = append(details, &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "queue",
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, queue.ErrBufferFull), errors.As(err, &ne), errors.Is(err, io.EOF):
+		code, reason = codes.Unavailable, "UNAVAILABLE"
+		details = append(details, &errdetails.RetryInfo{RetryDelay: retryDelay})
+	}
+
+	s := status.New(code, err.Error())
+	details = append(details,
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain},
+		messagesError(code, err.Error()),
+	)
+	for _, d := range details {
+		// the details are set one by one, so an unsupported one does not
+		// drop the others.
+		if ds, derr := s.WithDetails(d); derr == nil {
+			s = ds
+		}
+	}
+
+	return s
+}
+
+// httpCodes are the codes of the MessagesError of the older clients.
+var httpCodes = map[codes.Code]int32{
+	codes.InvalidArgument:    400,
+	codes.Unauthenticated:    401,
+	codes.PermissionDenied:   403,
+	codes.NotFound:           404,
+	codes.AlreadyExists:      409,
+	codes.Aborted:            409,
+	codes.FailedPrecondition: 412,
+	codes.ResourceExhausted:  429,
+	codes.Unimplemented:      501,
+	codes.Unavailable:        503,
+}
+
+// messagesError returns the MessagesError of the older clients.
+func messagesError(code codes.Code, msg string) *pb.MessagesError {
+	c, ok := httpCodes[code]
+	if !ok {
+		c = 500
+	}
+
+	return &pb.MessagesError{
+		Code:    c,
+		Message: msg,
+	}
+}
+
+// Trailers of the status of the RPCs failed with LegacyErrors.
+const (
+	// StatusCodeTrailer is the gRPC status code, in decimal.
+	StatusCodeTrailer = "x-status-code"
+	// StatusDetailsTrailer is the google.rpc.Status, with the details,
+	// encoded as protobuf.
+	StatusDetailsTrailer = "x-status-details-bin"
+)
+
+// fail returns the error field and the error of a failed RPC, the error
+// field is always the MessagesError of the older clients. By default the
+// error is the gRPC status of err, which carries the MessagesError on its
+// details too. With LegacyErrors the error is nil, as before the status
+// codes, so the response reaches the client, and the status is sent on
+// the StatusCodeTrailer and StatusDetailsTrailer trailers instead. Every
+// error of the unary RPCs goes through fail, including the ones of the
+// authentication, the tenants and the permissions.
+func (s *Service) fail(ctx context.Context, err error) (*pb.MessagesError, error) {
+	st := toStatus(err)
+	e := messagesError(st.Code(), st.Message())
+	if !s.LegacyErrors {
+		return e, st.Err()
+	}
+
+	md := metadata.Pairs(StatusCodeTrailer, strconv.Itoa(int(st.Code())))
+	if b, merr := proto.Marshal(st.Proto()); merr == nil {
+		md.Append(StatusDetailsTrailer, string(b))
+	}
+	if terr := grpc.SetTrailer(ctx, md); terr != nil {
+		slog.Debug("could not set the status trailers", "error", terr)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	pb "github.com/microapis/messages-core/proto"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+// trailerStream captures the trailers set by an RPC.
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestFail(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+		http int32
+	}{
+		{&invalidArgument{"id", fmt.Errorf("bad id")}, codes.InvalidArgument, 400},
+		{fmt.Errorf("message: %w", store.ErrNotFound), codes.NotFound, 404},
+		{fmt.Errorf("revision: %w", store.ErrConflict), codes.Aborted, 409},
+		{&rejection{reason: "bad content"}, codes.FailedPrecondition, 412},
+		{ErrQuotaExceeded, codes.ResourceExhausted, 429},
+		{queue.ErrBufferFull, codes.Unavailable, 503},
+		{fmt.Errorf("boom"), codes.Internal, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			s := &Service{}
+			e, err := s.fail(context.Background(), tt.err)
+			if e == nil || e.Code != tt.http {
+				t.Errorf("error field = %v, want code %d", e, tt.http)
+			}
+			if got := status.Code(err); got != tt.code {
+				t.Errorf("status code = %v, want %v", got, tt.code)
+			}
+
+			s.LegacyErrors = true
+			stream := &trailerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			e, err = s.fail(ctx, tt.err)
+			if err != nil {
+				t.Errorf("legacy error = %v, want nil", err)
+			}
+			if e == nil || e.Code != tt.http {
+				t.Errorf("legacy error field = %v, want code %d", e, tt.http)
+			}
+
+			if v := stream.trailer.Get(StatusCodeTrailer); len(v) != 1 || v[0] != strconv.Itoa(int(tt.code)) {
+				t.Errorf("%s trailer = %v, want %d", StatusCodeTrailer, v, tt.code)
+			}
+			v := stream.trailer.Get(StatusDetailsTrailer)
+			if len(v) != 1 {
+				t.Fatalf("%s trailer = %v, want one status", StatusDetailsTrailer, v)
+			}
+			var st spb.Status
+			if err := proto.Unmarshal([]byte(v[0]), &st); err != nil {
+				t.Fatal(err)
+			}
+			if codes.Code(st.Code) != tt.code {
+				t.Errorf("trailer status code = %v, want %v", codes.Code(st.Code), tt.code)
+			}
+
+			var info bool
+			for _, d := range status.FromProto(&st).Details() {
+				if ei, ok := d.(*errdetails.ErrorInfo); ok && ei.Domain == errorDomain {
+					info = true
+				}
+			}
+			if !info {
+				t.Error("trailer status without ErrorInfo")
+			}
+		})
+	}
+}
+
+func TestLegacyErrorsOnPermissionDenied(t *testing.T) {
+	// the tenant of the request is unknown, so every RPC fails before
+	// reaching the scheduler.
+	s := &Service{LegacyErrors: true, tenants: map[string]*tenantService{}}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), &trailerStream{})
+
+	tests := []struct {
+		rpc  string
+		call func() (*pb.MessagesError, error)
+	}{
+		{"Put", func() (*pb.MessagesError, error) {
+			r, err := s.Put(ctx, &pb.MessagePutRequest{Channel: "email"})
+			return r.GetError(), err
+		}},
+		{"Get", func() (*pb.MessagesError, error) {
+			r, err := s.Get(ctx, &pb.MessageGetRequest{})
+			return r.GetError(), err
+		}},
+		{"Cancel", func() (*pb.MessagesError, error) {
+			r, err := s.Cancel(ctx, &pb.MessageCancelRequest{})
+			return r.GetError(), err
+		}},
+		{"GetChannel", func() (*pb.MessagesError, error) {
+			r, err := s.GetChannel(ctx, &pb.ChannelGetRequest{})
+			return r.GetError(), err
+		}},
+	}
+
+	for _, tt := range tests {
+		e, err := tt.call()
+		if err != nil {
+			t.Errorf("%s error = %v, want nil", tt.rpc, err)
+		}
+		if e.GetCode() != 403 {
+			t.Errorf("%s error field = %v, want code 403", tt.rpc, e)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/microapis/messages-core/channel"
+	"github.com/microapis/messages-core/metrics"
+)
+
+func TestLabels(t *testing.T) {
+	s := &service{channels: map[string]*channel.Channel{
+		"email": {Name: "email", Providers: []*channel.Provider{{Name: "sendgrid"}}},
+	}}
+
+	tests := []struct {
+		channel, provider string
+		wantChannel       string
+		wantProvider      string
+	}{
+		{"email", "sendgrid", "email", "sendgrid"},
+		{"email", "", "email", ""},
+		{"email", "made-up", "email", metrics.Unknown},
+		{"made-up", "sendgrid", metrics.Unknown, metrics.Unknown},
+	}
+
+	for _, tt := range tests {
+		c, p := s.labels(tt.channel, tt.provider)
+		if c != tt.wantChannel || p != tt.wantProvider {
+			t.Errorf("labels(%q, %q) = %q, %q, want %q, %q", tt.channel, tt.provider, c, p, tt.wantChannel, tt.wantProvider)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/oklog/ulid"
+)
+
+// RetryPolicy describes how the failed deliveries are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of delivery attempts of a message, 0 or 1
+	// disables the retries.
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts" json:"max_attempts"`
+
+	// Backoff is the delay before the first retry, doubled on every
+	// following attempt.
+	Backoff time.Duration `yaml:"backoff" toml:"backoff" json:"backoff"`
+
+	// MaxBackoff caps the delay between two attempts, 0 does not cap it.
+	MaxBackoff time.Duration `yaml:"max_backoff" toml:"max_backoff" json:"max_backoff"`
+}
+
+// delay returns the time to wait after the given failed attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return d
+}
+
+// last reports whether attempt is the last delivery attempt, after which
+// a failed message is left failed-deliver.
+func (p RetryPolicy) last(attempt int) bool {
+	return attempt >= p.MaxAttempts
+}
+
+// scheduleRetry pushes the message id back to the queue after its failed
+// attempt, due after the delay of the policy, unless it was the last one.
+// The retry waits on the queue, so it survives a restart.
+func (s *service) scheduleRetry(id ulid.ULID, attempt int32) error {
+	if s.retry.last(int(attempt)) {
+		s.log.Warn("message failed its last delivery attempt", "id", id, "attempt", attempt)
+		return nil
+	}
+
+	if err := s.pq.PushAt(id, time.Now().Add(s.retry.delay(int(attempt)))); err != nil {
+		return err
+	}
+	s.notify()
+
+	return nil
+}
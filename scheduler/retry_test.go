@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/message"
+)
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.delay(tt.attempt); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	// without MaxBackoff the delay keeps doubling.
+	if got := (RetryPolicy{Backoff: time.Second}).delay(5); got != 16*time.Second {
+		t.Errorf("delay(5) without max backoff = %v, want %v", got, 16*time.Second)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	s := newService(t)
+	s.retry = RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}
+	s.delivery = func(string) error { return errors.New("provider down") }
+
+	m := newMessage("email", "content")
+	if err := s.Put(m); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= s.retry.MaxAttempts; attempt++ {
+		id, err := s.pq.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id == nil || *id != m.ID {
+			t.Fatalf("attempt %d: Pop() = %v, want %v", attempt, id, m.ID)
+		}
+
+		start := time.Now()
+		s.send(*id)
+
+		if got := statusOf(t, s, m.ID); got != message.FailedDeliver {
+			t.Errorf("attempt %d: status = %s, want %s", attempt, got, message.FailedDeliver)
+		}
+
+		// every attempt but the last is retried after the backoff.
+		_, due, err := s.pq.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := attempt == s.retry.MaxAttempts
+		if got := queued(t, s, m.ID); got == last {
+			t.Errorf("attempt %d: queued = %v, want %v", attempt, got, !last)
+		}
+		if want := start.Add(s.retry.delay(attempt)); !last && (due.Before(want.Add(-time.Second)) || due.After(want.Add(time.Second))) {
+			t.Errorf("attempt %d: retry due at %v, want about %v", attempt, due, want)
+		}
+	}
+
+	history, err := s.ms.GetHistory(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attempts int32
+	for _, tr := range history {
+		if tr.To == message.FailedDeliver {
+			attempts++
+			if tr.Attempt != attempts {
+				t.Errorf("failed-deliver attempt = %d, want %d", tr.Attempt, attempts)
+			}
+		}
+	}
+	if attempts != int32(s.retry.MaxAttempts) {
+		t.Errorf("history has %d failed attempts, want %d", attempts, s.retry.MaxAttempts)
+	}
+}
@@ -1,40 +1,186 @@
 package scheduler
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"time"
 
+	"github.com/microapis/messages-core/archive"
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/content"
 	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/store"
+	"github.com/microapis/messages-core/templates"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/microapis/messages-core/tracing"
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 
 	pb "github.com/microapis/messages-core/proto"
 	"github.com/oklog/ulid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ pb.SchedulerServiceServer = (*Service)(nil)
 
 // Service ...
 type Service struct {
+	// LegacyErrors returns the errors on the error field of the responses
+	// with an OK status, for the clients older than the status codes, and
+	// the status on the trailers.
+	LegacyErrors bool
+
+	tenants map[string]*tenantService
+}
+
+// tenantService is the scheduler of a tenant.
+type tenantService struct {
 	schedulerSvc SchedulerService
+	limiter      *rate.Limiter
+}
+
+// NewRPC starts a scheduler for the default tenant and for each one of
+// tenants, which may include the limits of the default tenant.
+func NewRPC(config StorageConfig, tenants []tenant.Config) (*Service, error) {
+	s := &Service{
+		tenants: make(map[string]*tenantService),
+	}
+
+	// the default tenant is always served, with the limits of the entry
+	// without id if any.
+	all := []tenant.Config{{ID: tenant.Default}}
+	config.Tenants = nil
+	for _, t := range tenants {
+		if t.ID == tenant.Default {
+			all[0] = t
+			continue
+		}
+		all = append(all, t)
+		config.Tenants = append(config.Tenants, t.ID)
+	}
+
+	for _, t := range all {
+		tc, err := config.For(t)
+		if err != nil {
+			return nil, err
+		}
+
+		ts := &tenantService{
+			schedulerSvc: New(tc),
+		}
+		if t.Rate > 0 {
+			ts.limiter = rate.NewLimiter(rate.Limit(t.Rate), t.Burst)
+		}
+		s.tenants[t.ID] = ts
+	}
+
+	return s, nil
+}
+
+// scheduler returns the scheduler of the tenant of ctx, which is the one
+// of the authenticated client or the one sent on the metadata.
+func (s *Service) scheduler(ctx context.Context) (SchedulerService, string, error) {
+	id := tenant.FromContext(ctx)
+	if c, ok := auth.FromContext(ctx); ok {
+		if id != tenant.Default && id != c.Tenant {
+			return nil, "", status.Errorf(codes.PermissionDenied, "client %q is not allowed on tenant %q", c.ID, id)
+		}
+		id = c.Tenant
+	}
+
+	ts, ok := s.tenants[id]
+	if !ok {
+		return nil, "", status.Errorf(codes.PermissionDenied, "unknown tenant %q", id)
+	}
+	if ts.limiter != nil && !ts.limiter.Allow() {
+		return nil, "", status.Errorf(codes.ResourceExhausted, "rate limit of tenant %q exceeded", id)
+	}
+
+	return ts.schedulerSvc, id, nil
 }
 
-// NewRPC ...
-func NewRPC(config StorageConfig) *Service {
-	return &Service{
-		schedulerSvc: New(config),
+// Alive reports whether the run loops of the schedulers of every tenant
+// are running.
+func (s *Service) Alive(ctx context.Context) error {
+	for id, ts := range s.tenants {
+		if err := ts.schedulerSvc.Alive(); err != nil {
+			if id == tenant.Default {
+				return err
+			}
+			return fmt.Errorf("tenant %s: %v", id, err)
+		}
 	}
+
+	return nil
+}
+
+// CheckBackends checks the health of the backends of the channels of
+// every tenant, by channel name namespaced by tenant.
+func (s *Service) CheckBackends(ctx context.Context) map[string]error {
+	errs := make(map[string]error)
+	for id, ts := range s.tenants {
+		for name, err := range ts.schedulerSvc.CheckBackends(ctx) {
+			errs[tenant.Key(id, name)] = err
+		}
+	}
+
+	return errs
 }
 
 // Put ...
 func (s *Service) Put(ctx context.Context, r *pb.MessagePutRequest) (*pb.MessagePutResponse, error) {
 	channel := r.GetChannel()
 	provider := r.GetProvider()
-	content := r.GetContent()
 	delay := r.GetDelay()
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Put][Request] channel = %v provider = %v delay = %v", channel, provider, delay))
+	l := slog.With("rpc", "Put")
+	l.Info("request", "channel", channel, "provider", provider, "delay", delay, "content_type", r.GetContentType(), "template", r.GetTemplate(), "locale", r.GetLocale())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessagePutResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(channel) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessagePutResponse{Error: e}, err
+	}
+
+	var value, contentType, locale string
+	if name := r.GetTemplate(); name != "" {
+		// the variables are stored as the content until the approval
+		// renders the template with them.
+		if r.GetContent() != "" || r.GetAnyContent() != nil || r.GetContentType() != "" {
+			err = &invalidArgument{"template", errors.New("template and content are exclusive")}
+		} else if locale, err = templates.ParseLocale(r.GetLocale()); err != nil {
+			err = &invalidArgument{"locale", err}
+		} else {
+			var b []byte
+			if b, err = json.Marshal(r.GetVariables().AsMap()); err != nil {
+				err = &invalidArgument{"variables", err}
+			}
+			value = string(b)
+		}
+	} else {
+		value, contentType, err = content.Encode(r.GetContentType(), r.GetContent(), r.GetAnyContent())
+		if err != nil {
+			err = &invalidArgument{"content", err}
+		}
+	}
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessagePutResponse{Error: e}, err
+	}
 
 	entropy := rand.New(rand.NewSource(time.Now().UnixNano()))
 	id, err := ulid.New(
@@ -42,26 +188,37 @@ func (s *Service) Put(ctx context.Context, r *pb.MessagePutRequest) (*pb.Message
 		entropy,
 	)
 	if err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Put][Error] error = %v", err))
-		return &pb.MessagePutResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessagePutResponse{Error: e}, err
 	}
 
-	if err := s.schedulerSvc.Put(id, channel, provider, content, message.Pending); err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Put][Error] error = %v", err))
-		return &pb.MessagePutResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+	m := message.Message{
+		ID:       id,
+		Channel:  channel,
+		Provider: provider,
+		Content:  value,
+		Status:   message.Received,
+
+		ContentType: contentType,
+	}
+	m.Client = clientID(ctx)
+	m.TraceContext = tracing.Inject(ctx)
+	m.Tenant = tenantID
+	if name := r.GetTemplate(); name != "" {
+		m.Template = name
+		m.TemplateVersion = r.GetTemplateVersion()
+		m.Locale = locale
+		m.Render = true
+	}
+
+	if err := svc.Put(m); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessagePutResponse{Error: e}, err
 	}
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Put][Response] id = %v", id.String()))
+	l.Info("response", "id", id.String())
 	return &pb.MessagePutResponse{
 		Data: &pb.MessagePutDataResponse{
 			Id: id.String(),
@@ -71,99 +228,566 @@ func (s *Service) Put(ctx context.Context, r *pb.MessagePutRequest) (*pb.Message
 
 // Get ...
 func (s *Service) Get(ctx context.Context, r *pb.MessageGetRequest) (*pb.MessageGetResponse, error) {
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Get][Request] id = %v", r.GetId()))
+	l := slog.With("rpc", "Get")
+	l.Info("request", "id", r.GetId())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
 
 	id, err := ulid.Parse(r.GetId())
 	if err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Get][Error] error = %v", err))
-		return &pb.MessageGetResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+		err = &invalidArgument{"id", err}
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetResponse{Error: e}, err
 	}
 
-	msg, err := s.schedulerSvc.Get(id)
+	msg, err := svc.Get(id)
 	if err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Get][Error] error = %v", err))
-		return &pb.MessageGetResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetResponse{Error: e}, err
+	}
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanRead(msg) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, hidden(id))
+		return &pb.MessageGetResponse{Error: e}, err
 	}
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Get][Response] id = %v", id.String()))
+	data := &pb.Message{
+		Id:       r.Id,
+		Content:  string(msg.Content),
+		Channel:  string(msg.Channel),
+		Provider: string(msg.Provider),
+		Status:   string(msg.Status),
+		Client:   msg.Client,
+		Tenant:   msg.Tenant,
+		Revision: msg.Revision,
+
+		ContentType:     msg.ContentType,
+		Template:        msg.Template,
+		TemplateVersion: msg.TemplateVersion,
+		Locale:          msg.Locale,
+	}
+
+	if r.GetWithHistory() {
+		history, err := svc.GetHistory(id)
+		if err != nil {
+			l.Error("request failed", "error", err)
+			e, err := s.fail(ctx, err)
+			return &pb.MessageGetResponse{Error: e}, err
+		}
+
+		for _, t := range history {
+			data.History = append(data.History, t.ToProto())
+		}
+	}
+
+	if r.GetWithVersions() {
+		versions, err := svc.GetVersions(id)
+		if err != nil {
+			l.Error("request failed", "error", err)
+			e, err := s.fail(ctx, err)
+			return &pb.MessageGetResponse{Error: e}, err
+		}
+
+		for _, v := range versions {
+			data.Versions = append(data.Versions, v.ToProto())
+		}
+	}
+
+	l.Info("response", "id", id.String(), "channel", msg.Channel, "status", msg.Status)
 	return &pb.MessageGetResponse{
-		Data: &pb.Message{
-			Id:       r.Id,
-			Content:  string(msg.Content),
-			Channel:  string(msg.Channel),
-			Provider: string(msg.Provider),
-			Status:   string(msg.Status),
-		},
+		Data: data,
 	}, nil
 }
 
 // Update ...
 func (s *Service) Update(ctx context.Context, r *pb.MessageUpdateRequest) (*pb.MessageUpdateResponse, error) {
 	id := r.GetId()
-	content := r.GetContent()
+	value := r.GetContent()
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Update][Request] id = %v content = %v", id, content))
+	l := slog.With("rpc", "Update")
+	l.Info("request", "id", id, "content_bytes", len(value), "revision", r.GetRevision())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageUpdateResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
 
 	uid, err := ulid.Parse(r.GetId())
 	if err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Update][Error] error = %v", err))
-		return &pb.MessageUpdateResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+		err = &invalidArgument{"id", err}
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageUpdateResponse{Error: e}, err
+	}
+
+	if err := s.authorize(ctx, svc, uid, func(c *auth.Client, m *message.Message) bool { return c.CanModify(m) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageUpdateResponse{Error: e}, err
+	}
+
+	if a := r.GetAnyContent(); a != nil {
+		if value, _, err = content.Encode(content.Protobuf, value, a); err != nil {
+			err = &invalidArgument{"content", err}
+			l.Error("request failed", "error", err)
+			e, err := s.fail(ctx, err)
+			return &pb.MessageUpdateResponse{Error: e}, err
+		}
 	}
 
-	if err := s.schedulerSvc.Update(uid, content); err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Update][Error] error = %v", err))
-		return &pb.MessageUpdateResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+	if err := svc.Update(uid, value, r.GetRevision(), clientID(ctx)); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageUpdateResponse{Error: e}, err
 	}
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Update][Response]"))
+	l.Info("response")
 	return &pb.MessageUpdateResponse{}, nil
 }
 
 // Cancel ...
 func (s *Service) Cancel(ctx context.Context, r *pb.MessageCancelRequest) (*pb.MessageCancelResponse, error) {
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Update][Request] id = %v", r.GetId()))
+	l := slog.With("rpc", "Cancel")
+	l.Info("request", "id", r.GetId(), "revision", r.GetRevision())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageCancelResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
 
 	id, err := ulid.Parse(r.GetId())
 	if err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Cancel][Error] error = %v", err))
-		return &pb.MessageCancelResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+		err = &invalidArgument{"id", err}
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageCancelResponse{Error: e}, err
 	}
 
-	if err := s.schedulerSvc.Cancel(id); err != nil {
-		log.Println(fmt.Sprintf("[gRPC][MessagesService][Cancel][Error] error = %v", err))
-		return &pb.MessageCancelResponse{
-			Error: &pb.MessagesError{
-				Code:    500,
-				Message: err.Error(),
-			},
-		}, nil
+	if err := s.authorize(ctx, svc, id, func(c *auth.Client, m *message.Message) bool { return c.CanModify(m) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageCancelResponse{Error: e}, err
 	}
 
-	log.Println(fmt.Sprintf("[gRPC][MessagesService][Cancel][Response]"))
+	if err := svc.Cancel(id, r.GetRevision(), clientID(ctx)); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageCancelResponse{Error: e}, err
+	}
+
+	l.Info("response")
 	return &pb.MessageCancelResponse{}, nil
 }
+
+// GetHistory ...
+func (s *Service) GetHistory(ctx context.Context, r *pb.MessageGetHistoryRequest) (*pb.MessageGetHistoryResponse, error) {
+	l := slog.With("rpc", "GetHistory")
+	l.Info("request", "id", r.GetId())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetHistoryResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	id, err := ulid.Parse(r.GetId())
+	if err != nil {
+		err = &invalidArgument{"id", err}
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetHistoryResponse{Error: e}, err
+	}
+
+	if err := s.authorize(ctx, svc, id, func(c *auth.Client, m *message.Message) bool { return c.CanRead(m) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetHistoryResponse{Error: e}, err
+	}
+
+	history, err := svc.GetHistory(id)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageGetHistoryResponse{Error: e}, err
+	}
+
+	data := make([]*pb.StatusTransition, 0, len(history))
+	for _, t := range history {
+		data = append(data, t.ToProto())
+	}
+
+	l.Info("response", "id", id.String(), "transitions", len(data))
+	return &pb.MessageGetHistoryResponse{
+		Data: data,
+	}, nil
+}
+
+// Export ...
+func (s *Service) Export(ctx context.Context, r *pb.MessageExportRequest) (*pb.MessageExportResponse, error) {
+	l := slog.With("rpc", "Export")
+	l.Info("request", "channel", r.GetChannel(), "status", r.GetStatus(), "since", r.GetSince(), "until", r.GetUntil(), "archived", r.GetArchived(), "format", r.GetFormat())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageExportResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.Can(auth.Admin) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageExportResponse{Error: e}, err
+	}
+
+	f := archive.Filter{
+		Channel:  r.GetChannel(),
+		Status:   r.GetStatus(),
+		Archived: r.GetArchived(),
+	}
+	if r.GetSince() > 0 {
+		f.Since = time.Unix(0, r.GetSince()*int64(time.Millisecond))
+	}
+	if r.GetUntil() > 0 {
+		f.Until = time.Unix(0, r.GetUntil()*int64(time.Millisecond))
+	}
+
+	files, n, err := svc.Export(f, r.GetFormat())
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.MessageExportResponse{Error: e}, err
+	}
+
+	l.Info("response", "files", files, "count", n)
+	return &pb.MessageExportResponse{
+		Data: &pb.MessageExportDataResponse{
+			Files: files,
+			Count: int64(n),
+		},
+	}, nil
+}
+
+// Backup ...
+func (s *Service) Backup(r *pb.BackupRequest, stream pb.SchedulerService_BackupServer) error {
+	l := slog.With("rpc", "Backup")
+	l.Info("request")
+
+	svc, tenantID, err := s.scheduler(stream.Context())
+	if err != nil {
+		l.Error("request failed", "error", err)
+		return err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(stream.Context(), func(c *auth.Client) bool { return c.Can(auth.Admin) }); err != nil {
+		l.Error("request failed", "error", err)
+		return err
+	}
+
+	// the backup includes every tenant, so it is kept to the default one.
+	if tenantID != tenant.Default {
+		err := status.Errorf(codes.PermissionDenied, "backup is not allowed on tenant %q", tenantID)
+		l.Error("request failed", "error", err)
+		return err
+	}
+
+	w := bufio.NewWriterSize(&chunkWriter{stream}, backupChunkSize)
+	if err := svc.Backup(w); err != nil {
+		l.Error("request failed", "error", err)
+		return toStatus(err).Err()
+	}
+	if err := w.Flush(); err != nil {
+		l.Error("request failed", "error", err)
+		return toStatus(err).Err()
+	}
+
+	l.Info("response")
+	return nil
+}
+
+// GetChannel ...
+func (s *Service) GetChannel(ctx context.Context, r *pb.ChannelGetRequest) (*pb.ChannelGetResponse, error) {
+	l := slog.With("rpc", "GetChannel")
+	l.Info("request", "channel", r.GetName())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.ChannelGetResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(r.GetName()) || c.Can(auth.Admin) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.ChannelGetResponse{Error: e}, err
+	}
+
+	ch, err := svc.GetChannel(r.GetName())
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.ChannelGetResponse{Error: e}, err
+	}
+
+	// the secret values never leave the service.
+	ch = ch.Redacted()
+	data := &pb.Channel{
+		Name: ch.Name,
+		Host: ch.Host,
+		Port: ch.Port,
+	}
+	for _, p := range ch.Providers {
+		data.Providers = append(data.Providers, &pb.Provider{
+			Name:   p.Name,
+			Params: p.Params,
+		})
+	}
+	if sc := ch.Schema; sc != nil {
+		data.Schema = &pb.ContentSchema{
+			Json:        sc.JSON,
+			Descriptor_: sc.Descriptor,
+			Message:     sc.Message,
+		}
+	}
+
+	l.Info("response", "channel", ch.Name, "providers", ch.ProvidersNames())
+	return &pb.ChannelGetResponse{
+		Data: data,
+	}, nil
+}
+
+// CreateTemplate ...
+func (s *Service) CreateTemplate(ctx context.Context, r *pb.TemplateCreateRequest) (*pb.TemplateCreateResponse, error) {
+	l := slog.With("rpc", "CreateTemplate")
+	l.Info("request", "channel", r.GetChannel(), "name", r.GetName(), "locale", r.GetLocale(), "engine", r.GetEngine(), "content_type", r.GetContentType())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateCreateResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(r.GetChannel()) && c.Can(auth.Templates) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateCreateResponse{Error: e}, err
+	}
+
+	t := templates.Template{
+		Channel:     r.GetChannel(),
+		Name:        r.GetName(),
+		Locale:      r.GetLocale(),
+		Engine:      r.GetEngine(),
+		Body:        r.GetBody(),
+		ContentType: r.GetContentType(),
+		Client:      clientID(ctx),
+	}
+
+	created, err := svc.CreateTemplate(t)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateCreateResponse{Error: e}, err
+	}
+
+	l.Info("response", "locale", created.Locale, "version", created.Version)
+	return &pb.TemplateCreateResponse{
+		Data: created.ToProto(),
+	}, nil
+}
+
+// GetTemplate ...
+func (s *Service) GetTemplate(ctx context.Context, r *pb.TemplateGetRequest) (*pb.TemplateGetResponse, error) {
+	l := slog.With("rpc", "GetTemplate")
+	l.Info("request", "channel", r.GetChannel(), "name", r.GetName(), "locale", r.GetLocale(), "version", r.GetVersion())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateGetResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(r.GetChannel()) || c.Can(auth.Admin) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateGetResponse{Error: e}, err
+	}
+
+	t, err := svc.GetTemplate(r.GetChannel(), r.GetName(), r.GetLocale(), r.GetVersion())
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateGetResponse{Error: e}, err
+	}
+
+	resp := &pb.TemplateGetResponse{
+		Data: t.ToProto(),
+	}
+
+	if r.GetWithVersions() {
+		versions, err := svc.GetTemplateVersions(r.GetChannel(), r.GetName(), r.GetLocale())
+		if err != nil {
+			l.Error("request failed", "error", err)
+			e, err := s.fail(ctx, err)
+			return &pb.TemplateGetResponse{Error: e}, err
+		}
+
+		for _, v := range versions {
+			resp.Versions = append(resp.Versions, v.ToProto())
+		}
+	}
+
+	l.Info("response", "locale", t.Locale, "version", t.Version)
+	return resp, nil
+}
+
+// PreviewTemplate renders a template without putting a message.
+func (s *Service) PreviewTemplate(ctx context.Context, r *pb.TemplatePreviewRequest) (*pb.TemplatePreviewResponse, error) {
+	l := slog.With("rpc", "PreviewTemplate")
+	l.Info("request", "channel", r.GetChannel(), "name", r.GetName(), "locale", r.GetLocale(), "version", r.GetVersion())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplatePreviewResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(r.GetChannel()) || c.Can(auth.Admin) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplatePreviewResponse{Error: e}, err
+	}
+
+	t, value, err := svc.RenderTemplate(r.GetChannel(), r.GetName(), r.GetLocale(), r.GetVersion(), r.GetVariables().AsMap())
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplatePreviewResponse{Error: e}, err
+	}
+
+	l.Info("response", "locale", t.Locale, "version", t.Version)
+	return &pb.TemplatePreviewResponse{
+		Data: &pb.TemplatePreviewDataResponse{
+			Content:     value,
+			ContentType: t.ContentType,
+			Version:     t.Version,
+			Locale:      t.Locale,
+		},
+	}, nil
+}
+
+// RollbackTemplate ...
+func (s *Service) RollbackTemplate(ctx context.Context, r *pb.TemplateRollbackRequest) (*pb.TemplateRollbackResponse, error) {
+	l := slog.With("rpc", "RollbackTemplate")
+	l.Info("request", "channel", r.GetChannel(), "name", r.GetName(), "locale", r.GetLocale(), "version", r.GetVersion())
+
+	svc, tenantID, err := s.scheduler(ctx)
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateRollbackResponse{Error: e}, err
+	}
+	l = l.With("tenant", tenantID)
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanSend(r.GetChannel()) && c.Can(auth.Templates) }); err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateRollbackResponse{Error: e}, err
+	}
+
+	t, err := svc.RollbackTemplate(r.GetChannel(), r.GetName(), r.GetLocale(), r.GetVersion(), clientID(ctx))
+	if err != nil {
+		l.Error("request failed", "error", err)
+		e, err := s.fail(ctx, err)
+		return &pb.TemplateRollbackResponse{Error: e}, err
+	}
+
+	l.Info("response", "locale", t.Locale, "version", t.Version, "rollback_of", t.RollbackOf)
+	return &pb.TemplateRollbackResponse{
+		Data: t.ToProto(),
+	}, nil
+}
+
+// clientID returns the id of the authenticated client of ctx, empty when
+// the authentication is disabled.
+func clientID(ctx context.Context) string {
+	if c, ok := auth.FromContext(ctx); ok {
+		return c.ID
+	}
+
+	return ""
+}
+
+// authorize checks allow over the message with the given id for the
+// client of ctx, it does nothing when the authentication is disabled. A
+// message the client may not read is not found, as on Get.
+func (s *Service) authorize(ctx context.Context, svc SchedulerService, id ulid.ULID, allow func(c *auth.Client, m *message.Message) bool) error {
+	if _, ok := auth.FromContext(ctx); !ok {
+		return nil
+	}
+
+	m, err := svc.Get(id)
+	if err != nil {
+		return toStatus(err).Err()
+	}
+
+	if err := auth.Authorize(ctx, func(c *auth.Client) bool { return c.CanRead(m) }); err != nil {
+		slog.Warn("message hidden from client", "id", id, "error", err)
+		return hidden(id)
+	}
+
+	return auth.Authorize(ctx, func(c *auth.Client) bool { return allow(c, m) })
+}
+
+// hidden is the error of a message the client may not read, the same as
+// the one of an unknown id, so the ids of the other clients are not
+// disclosed.
+func hidden(id ulid.ULID) error {
+	return fmt.Errorf("message %s: %w", id, store.ErrNotFound)
+}
+
+// backupChunkSize is the size of the chunks sent by Backup.
+const backupChunkSize = 64 * 1024
+
+// chunkWriter sends every write as a BackupChunk.
+type chunkWriter struct {
+	stream pb.SchedulerService_BackupServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	if err := w.stream.Send(&pb.BackupChunk{Data: data}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
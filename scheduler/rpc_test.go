@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/message"
+	pb "github.com/microapis/messages-core/proto"
+	"github.com/microapis/messages-core/tenant"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeHidesMessages(t *testing.T) {
+	svc := newService(t)
+	s := &Service{tenants: map[string]*tenantService{tenant.Default: {schedulerSvc: svc}}}
+
+	m := newMessage("email", "content")
+	m.Client = "owner"
+	if err := svc.Put(m); err != nil {
+		t.Fatal(err)
+	}
+	id := m.ID.String()
+
+	tests := []struct {
+		name   string
+		client *auth.Client
+		code   codes.Code
+	}{
+		{"owner", &auth.Client{ID: "owner"}, codes.OK},
+		{"other", &auth.Client{ID: "other"}, codes.NotFound},
+		{"reader", &auth.Client{ID: "reader", Permissions: []string{auth.ReadAll}}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		ctx := grpc.NewContextWithServerTransportStream(auth.NewContext(context.Background(), tt.client), &trailerStream{})
+
+		_, err := s.Get(ctx, &pb.MessageGetRequest{Id: id})
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s: Get() code = %v, want %v", tt.name, got, tt.code)
+		}
+		_, err = s.GetHistory(ctx, &pb.MessageGetHistoryRequest{Id: id})
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s: GetHistory() code = %v, want %v", tt.name, got, tt.code)
+		}
+	}
+
+	// the clients that may read the message but not cancel it are denied.
+	ctx := grpc.NewContextWithServerTransportStream(auth.NewContext(context.Background(), tests[2].client), &trailerStream{})
+	_, err := s.Cancel(ctx, &pb.MessageCancelRequest{Id: id})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Errorf("Cancel() by a reader code = %v, want %v", got, codes.PermissionDenied)
+	}
+	ctx = grpc.NewContextWithServerTransportStream(auth.NewContext(context.Background(), tests[1].client), &trailerStream{})
+	_, err = s.Cancel(ctx, &pb.MessageCancelRequest{Id: id})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("Cancel() by another client code = %v, want %v", got, codes.NotFound)
+	}
+
+	got, err := svc.Get(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status == message.Cancelled {
+		t.Error("message cancelled by a client that may not")
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	svc := newService(t)
+	s := &Service{tenants: map[string]*tenantService{tenant.Default: {schedulerSvc: svc}}}
+	ctx := grpc.NewContextWithServerTransportStream(auth.NewContext(context.Background(), &auth.Client{ID: "a"}), &trailerStream{})
+
+	m := newMessage("email", "content")
+	m.Client = "a"
+	if err := svc.Put(m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Cancel(ctx, &pb.MessageCancelRequest{Id: m.ID.String(), Revision: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.GetHistory(ctx, &pb.MessageGetHistoryRequest{Id: m.ID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*pb.StatusTransition{
+		{To: message.Received, Source: message.SourceAPI, Client: "a"},
+		{From: message.Received, To: message.Approved, Source: message.SourceAPI, Client: "a"},
+		{From: message.Approved, To: message.Cancelled, Source: message.SourceAPI, Client: "a"},
+	}
+	if len(r.GetData()) != len(want) {
+		t.Fatalf("GetHistory() = %v, want %d transitions", r.GetData(), len(want))
+	}
+	for i, tr := range r.GetData() {
+		if tr.From != want[i].From || tr.To != want[i].To || tr.Source != want[i].Source || tr.Client != want[i].Client || tr.Time == 0 {
+			t.Errorf("GetHistory()[%d] = %v, want %v", i, tr, want[i])
+		}
+	}
+
+	// Get includes the same history when asked for it.
+	g, err := s.Get(ctx, &pb.MessageGetRequest{Id: m.ID.String(), WithHistory: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.GetData().GetHistory()) != len(want) {
+		t.Errorf("Get() with history = %d transitions, want %d", len(g.GetData().GetHistory()), len(want))
+	}
+	if g, err := s.Get(ctx, &pb.MessageGetRequest{Id: m.ID.String()}); err != nil || len(g.GetData().GetHistory()) != 0 {
+		t.Errorf("Get() without history = %v, %v, want no transitions", g.GetData().GetHistory(), err)
+	}
+
+	if _, err := s.GetHistory(ctx, &pb.MessageGetHistoryRequest{Id: newMessage("email", "").ID.String()}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetHistory() of an unknown message = %v, want %v", err, codes.NotFound)
+	}
+}
+
+func TestTenants(t *testing.T) {
+	def := newService(t)
+	ms, err := def.ms.For("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pq, err := def.pq.For("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	billing := newService(t)
+	billing.ms, billing.pq, billing.tenant = ms, pq, "billing"
+
+	s := &Service{tenants: map[string]*tenantService{
+		tenant.Default: {schedulerSvc: def},
+		"billing":      {schedulerSvc: billing, limiter: rate.NewLimiter(0, 3)},
+	}}
+	ctx := func(c *auth.Client, tenantID string) context.Context {
+		ctx := context.Background()
+		if tenantID != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(tenant.Header, tenantID))
+		}
+		return grpc.NewContextWithServerTransportStream(auth.NewContext(ctx, c), &trailerStream{})
+	}
+	billingClient := &auth.Client{ID: "a", Tenant: "billing", Channels: []string{"*"}}
+
+	r, err := s.Put(ctx(billingClient, ""), &pb.MessagePutRequest{Channel: "email", Content: "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := r.GetData().GetId()
+
+	// the message is stored and queued on the tenant of the client only.
+	g, err := s.Get(ctx(billingClient, ""), &pb.MessageGetRequest{Id: id})
+	if err != nil || g.GetData().GetTenant() != "billing" {
+		t.Errorf("Get() on the tenant = %v, %v, want the message of billing", g.GetData(), err)
+	}
+	defaultClient := &auth.Client{ID: "a", Channels: []string{"*"}}
+	if _, err := s.Get(ctx(defaultClient, ""), &pb.MessageGetRequest{Id: id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() on the default tenant = %v, want %v", err, codes.NotFound)
+	}
+	for _, svc := range []*service{def, billing} {
+		if n, err := svc.pq.Len(); err != nil || (n == 1) != (svc == billing) {
+			t.Errorf("Len() of the queue of tenant %q = %d, %v", svc.tenant, n, err)
+		}
+	}
+
+	// a client cannot act on another tenant.
+	if _, err := s.Get(ctx(defaultClient, "billing"), &pb.MessageGetRequest{Id: id}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Get() on another tenant = %v, want %v", err, codes.PermissionDenied)
+	}
+
+	// the burst of the tenant is spent by the former 2 requests.
+	if _, err := s.Get(ctx(billingClient, ""), &pb.MessageGetRequest{Id: id}); err != nil {
+		t.Errorf("Get() within the rate of the tenant = %v", err)
+	}
+	if _, err := s.Get(ctx(billingClient, ""), &pb.MessageGetRequest{Id: id}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Get() over the rate of the tenant = %v, want %v", err, codes.ResourceExhausted)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	svc := newService(t)
+	s := &Service{tenants: map[string]*tenantService{tenant.Default: {schedulerSvc: svc}}}
+
+	m := newMessage("email", "content")
+	m.Client = "owner"
+	if err := svc.Put(m); err != nil {
+		t.Fatal(err)
+	}
+	id, unknown := m.ID.String(), newMessage("email", "").ID.String()
+
+	ctx := grpc.NewContextWithServerTransportStream(auth.NewContext(context.Background(), &auth.Client{ID: "owner"}), &trailerStream{})
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"Get unknown", func() error {
+			_, err := s.Get(ctx, &pb.MessageGetRequest{Id: unknown})
+			return err
+		}, codes.NotFound},
+		{"Update unknown", func() error {
+			_, err := s.Update(ctx, &pb.MessageUpdateRequest{Id: unknown, Content: "new", Revision: 1})
+			return err
+		}, codes.NotFound},
+		{"Cancel unknown", func() error {
+			_, err := s.Cancel(ctx, &pb.MessageCancelRequest{Id: unknown, Revision: 1})
+			return err
+		}, codes.NotFound},
+		{"Update stale", func() error {
+			_, err := s.Update(ctx, &pb.MessageUpdateRequest{Id: id, Content: "new", Revision: 2})
+			return err
+		}, codes.Aborted},
+		{"Cancel stale", func() error {
+			_, err := s.Cancel(ctx, &pb.MessageCancelRequest{Id: id, Revision: 2})
+			return err
+		}, codes.Aborted},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(tt.call()); got != tt.code {
+			t.Errorf("%s: code = %v, want %v", tt.name, got, tt.code)
+		}
+	}
+
+	got, err := svc.Get(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Content != "content" || got.Revision != 1 || got.Status == message.Cancelled {
+		t.Errorf("Get() = %q at revision %d, %s, want the message unchanged", got.Content, got.Revision, got.Status)
+	}
+}
@@ -1,64 +1,254 @@
 package scheduler
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/boltdb/bolt"
+	"github.com/microapis/messages-core/archive"
+	"github.com/microapis/messages-core/backend"
+	"github.com/microapis/messages-core/backup"
 	"github.com/microapis/messages-core/channel"
 	dbRedis "github.com/microapis/messages-core/channel/database/redis"
+	"github.com/microapis/messages-core/content"
 	"github.com/microapis/messages-core/message"
 	dbBolt "github.com/microapis/messages-core/message/database/bolt"
+	"github.com/microapis/messages-core/metrics"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/secret"
+	"github.com/microapis/messages-core/store"
+	"github.com/microapis/messages-core/templates"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/microapis/messages-core/tracing"
 	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SchedulerService stores and keep track of the statuses of messages.
 type SchedulerService interface {
-	// Put stores a message content and schedule the delivery on t time.
-	// TODO(ca): change subjectID params to ulid.ULID type
-	Put(id ulid.ULID, channel string, provider string, content string, status string) error
+	// Put stores the message m and schedule its delivery on the time
+	// encoded in its id.
+	Put(m message.Message) error
 
 	// Get retrieves the message with the given id.
 	//
-	// In case of any error the Message will be nil.
+	// In case of any error the Message will be nil. The methods on a
+	// message that does not exist fail with store.ErrNotFound.
 	Get(id ulid.ULID) (*message.Message, error)
 
-	// Update updates the content of the message with the given id.
-	Update(id ulid.ULID, content string) error
-
-	// Cancel cancel the message with the given id.
-	Cancel(id ulid.ULID) error
+	// Update updates the content of the message with the given id at the
+	// given revision and increases it, failing with store.ErrConflict if
+	// the message is at another one. The content keeps the type of the
+	// message and is approved as on Put, failing with ErrNotApproved
+	// without changing the message when rejected. The former content is
+	// kept as a version replaced by client.
+	Update(id ulid.ULID, content string, revision int32, client string) error
+
+	// Cancel cancel the message with the given id at the given revision on
+	// behalf of client, failing with store.ErrConflict if the message is
+	// at another one.
+	Cancel(id ulid.ULID, revision int32, client string) error
+
+	// GetVersions retrieves the former contents of the message with the
+	// given id, from the oldest to the newest.
+	GetVersions(id ulid.ULID) ([]*message.Version, error)
+
+	// GetHistory retrieves the status transitions of the message with the
+	// given id, from the oldest to the newest.
+	GetHistory(id ulid.ULID) ([]*message.Transition, error)
+
+	// Export writes the messages selected by f to archive files using
+	// format, or the configured format if empty. It returns the names of
+	// the files and the number of exported messages.
+	Export(f archive.Filter, format string) ([]string, int, error)
+
+	// GetChannel retrieves the channel with the given name, failing with
+	// store.ErrNotFound if it does not exist.
+	GetChannel(name string) (*channel.Channel, error)
+
+	// CreateTemplate stores t as the next version of its template.
+	CreateTemplate(t templates.Template) (*templates.Template, error)
+
+	// GetTemplate retrieves the given version of the variant of locale of
+	// the template of a channel, the latest one when version is 0. The
+	// methods on a template that does not exist fail with
+	// store.ErrNotFound.
+	GetTemplate(channel, name, locale string, version int32) (*templates.Template, error)
+
+	// GetTemplateVersions retrieves every version of the variant of locale
+	// of the template of a channel, from the oldest to the newest.
+	GetTemplateVersions(channel, name, locale string) ([]*templates.Template, error)
+
+	// RenderTemplate renders with vars the given version, the latest one
+	// when version is 0, of the variant of the template of a channel that
+	// best matches locale, falling back to its parents, the default locale
+	// and the variant without locale. It returns the rendered variant and
+	// the content.
+	RenderTemplate(channel, name, locale string, version int32, vars map[string]interface{}) (*templates.Template, string, error)
+
+	// RollbackTemplate stores the given version of the variant of locale of
+	// the template of a channel as the next one, on behalf of client.
+	RollbackTemplate(channel, name, locale string, version int32, client string) (*templates.Template, error)
+
+	// Backup writes a consistent snapshot of the messages, the priority
+	// queue and the channel registry to w.
+	Backup(w io.Writer) error
+
+	// Alive reports whether the run loop of the scheduler is running.
+	Alive() error
+
+	// CheckBackends checks the health of the backends of the channels,
+	// by channel name.
+	CheckBackends(ctx context.Context) map[string]error
 }
 
 // StorageConfig is a struct that will be deleted.
 type StorageConfig struct {
-	RedisURL string
+	Queue queue.Queue
 
 	MessageStore *dbBolt.MessageStore
 	ChannelStore *dbRedis.ChannelStore
 
+	// TemplateStore keeps the templates of the channels, nil disables
+	// them.
+	TemplateStore *dbBolt.TemplateStore
+
+	// DefaultLocale is the locale of the variants of the templates tried
+	// after the ones of the recipient, templates.DefaultLocale if empty.
+	DefaultLocale string
+
+	// Retention is the policy applied over the stored messages, nil keeps
+	// the messages forever.
+	Retention *message.RetentionPolicy
+
+	// Exporter writes the archive files, nil disables the exports.
+	Exporter *archive.Exporter
+
+	// Retry is the policy applied when a delivery fails.
+	Retry RetryPolicy
+
+	// Approval is ApprovalInline (default) or ApprovalAsync.
+	Approval string
+
+	// Backends are the clients of the channel backends, used to approve
+	// and deliver the messages when Approve and Delivery are nil.
+	Backends *backend.Clients
+
+	// Secrets resolve the secret references on the params of the
+	// providers.
+	Secrets secret.Resolvers
+
+	// MaxQueued is the maximum number of messages waiting on the queue,
+	// Put fails with ErrQuotaExceeded over it, checked again atomically
+	// when the approved message is pushed. 0 does not limit them.
+	MaxQueued int
+
+	// Tenants are the ids of the tenants other than the default one, their
+	// queues and channels are included on the backups.
+	Tenants []string
+
 	Approve  func(content string) (bool, error)
 	Delivery func(content string) error
 }
 
+// For returns the config of the scheduler of tenant t, with the stores
+// and the queue scoped to the tenant.
+func (c StorageConfig) For(t tenant.Config) (StorageConfig, error) {
+	cc := c
+	cc.MaxQueued = t.MaxQueued
+	if t.ID == tenant.Default {
+		return cc, nil
+	}
+	cc.Tenants = nil
+
+	ms, err := c.MessageStore.For(t.ID)
+	if err != nil {
+		return cc, err
+	}
+	cc.MessageStore = ms
+
+	if cc.Queue, err = c.Queue.For(t.ID); err != nil {
+		return cc, err
+	}
+
+	if c.ChannelStore != nil {
+		cc.ChannelStore = c.ChannelStore.For(t.ID)
+	}
+
+	if c.TemplateStore != nil {
+		if cc.TemplateStore, err = c.TemplateStore.For(t.ID); err != nil {
+			return cc, err
+		}
+	}
+
+	if c.Exporter != nil {
+		e := *c.Exporter
+		e.Store = ms
+		if e.Sink, err = c.Exporter.Sink.For(t.ID); err != nil {
+			return cc, err
+		}
+		cc.Exporter = &e
+	}
+
+	return cc, nil
+}
+
+// heartbeat is the interval of the beats of the run loop, Alive fails
+// after missing a few of them.
+const heartbeat = 5 * time.Second
+
+// ErrQuotaExceeded is returned by Put when the queue is full.
+var ErrQuotaExceeded = errors.New("quota of queued messages exceeded")
+
 // New builds a new message.Store backed by bolt DB.
 //
 // In case of any error it panics.
 func New(config StorageConfig) SchedulerService {
 	s := &service{
-		pq:  newPriorityQueue(config),
-		idc: make(chan ulid.ULID),
+		pq:         config.Queue,
+		wake:       make(chan struct{}, 1),
+		channels:   make(map[string]*channel.Channel),
+		validators: make(map[string]*compiled),
 
 		ms: config.MessageStore,
 		cs: config.ChannelStore,
+		ts: config.TemplateStore,
+
+		defaultLocale: config.DefaultLocale,
+
+		exporter:  config.Exporter,
+		retry:     config.Retry,
+		async:     config.Approval == ApprovalAsync,
+		backends:  config.Backends,
+		tenant:    config.MessageStore.Tenant,
+		log:       slog.With("tenant", config.MessageStore.Tenant),
+		secrets:   config.Secrets,
+		maxQueued: config.MaxQueued,
+		tenants:   config.Tenants,
 
-		approve:  config.Approve,
-		delivery: config.Delivery,
+		approve:   config.Approve,
+		delivery:  config.Delivery,
+		approving: make(map[ulid.ULID]bool),
+
+		beat: time.Now().UnixNano(),
 	}
 
+	metrics.RegisterQueue(s.tenant, s.pq)
+
 	go s.run()
+	go s.resume()
+
+	if config.Retention != nil {
+		go s.sweep(config.Retention)
+	}
 
 	return s
 }
@@ -67,63 +257,101 @@ var msgBucket = []byte("messages")
 
 type service struct {
 	db *bolt.DB
-	pq *priorityQueue
+	pq queue.Queue
 
-	idc chan ulid.ULID
+	// wake makes the run loop peek the queue again after a push.
+	wake chan struct{}
 
 	ms *dbBolt.MessageStore
 	cs *dbRedis.ChannelStore
+	ts *dbBolt.TemplateStore
+
+	// channels caches the channels read from cs, to approve and deliver
+	// the messages while redis is unavailable.
+	channelsMu sync.Mutex
+	channels   map[string]*channel.Channel
+
+	// validators caches the compiled schemas of the channels.
+	validatorsMu sync.Mutex
+	validators   map[string]*compiled
+
+	defaultLocale string
+
+	exporter  *archive.Exporter
+	retry     RetryPolicy
+	async     bool
+	backends  *backend.Clients
+	tenant    string
+	log       *slog.Logger
+	secrets   secret.Resolvers
+	maxQueued int
+	tenants   []string
 
 	approve  func(content string) (bool, error)
 	delivery func(content string) error
+
+	// approving holds the ids of the messages being approved, which
+	// resume skips.
+	approvingMu sync.Mutex
+	approving   map[ulid.ULID]bool
+
+	// beat is the last time, in unix nanoseconds, the run loop was alive.
+	beat int64
 }
 
 // Put ...
-func (s *service) Put(id ulid.ULID, channel string, provider string, content string, status string) error {
-	// ch, err := s.cs.Get(channel)
-	// if err != nil {
-	// 	return err
-	// }
-
-	// log.Println(ch.Address())
+func (s *service) Put(m message.Message) error {
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), m.TraceContext), "scheduler.Put",
+		tracing.Message(m.ID.String(), m.Channel, m.Provider),
+	)
+	err := s.put(ctx, m)
+	tracing.End(span, err)
 
-	ok, err := s.approve(content)
-	if err != nil {
-		// update status to crashed-approve
-		e := s.ms.UpdateStatus(id, message.CrashedApprove)
-		if e != nil {
-			return e
-		}
+	return err
+}
 
-		// TODO(ca): send callback when could not updated status
-		return err
-	}
-	if !ok {
-		// update status to failed-approve
-		err := s.ms.UpdateStatus(id, message.FailedApprove)
+// put stores the message m as received and runs its approval, which
+// enqueues it when approved. ctx carries the span of the Put.
+func (s *service) put(ctx context.Context, m message.Message) error {
+	if s.maxQueued > 0 {
+		n, err := s.pq.Len()
 		if err != nil {
 			return err
 		}
-
-		return errors.New("failed message")
+		if n >= s.maxQueued {
+			return ErrQuotaExceeded
+		}
 	}
 
-	m := message.Message{
-		ID:       id,
-		Content:  content,
-		Status:   status,
-		Channel:  channel,
-		Provider: provider,
+	// the approval is claimed before the message is stored, so resume
+	// never approves it too.
+	if !s.claim(m.ID) {
+		return fmt.Errorf("message %s is being approved: %w", m.ID, store.ErrConflict)
 	}
 
-	err = s.ms.AddMessage(m)
+	m.Status = message.Received
+	err := s.ms.AddMessage(m, message.SourceAPI)
 	if err != nil {
+		s.release(m.ID)
 		return err
 	}
+	s.count(&m, message.Received)
 
-	s.idc <- id
+	if s.async {
+		go func() {
+			defer s.release(m.ID)
 
-	return nil
+			actx := tracing.Extract(context.Background(), m.TraceContext)
+			if err := s.approveMessage(actx, &m, message.SourceAPI); err != nil {
+				s.log.Warn("message not approved", "id", m.ID, "error", err)
+			}
+		}()
+
+		return nil
+	}
+	defer s.release(m.ID)
+
+	return s.approveMessage(ctx, &m, message.SourceAPI)
 }
 
 // Get ...
@@ -137,37 +365,271 @@ func (s *service) Get(id ulid.ULID) (*message.Message, error) {
 }
 
 // Update ...
-func (s *service) Update(id ulid.ULID, content string) error {
-	err := s.ms.UpdateContent(id, content)
+func (s *service) Update(id ulid.ULID, value string, revision int32, client string) error {
+	ctx, span := tracing.Start(context.Background(), "scheduler.Update")
+	err := s.update(ctx, id, value, revision, client)
+	tracing.End(span, err)
+
+	return err
+}
+
+// update approves value, the new content of the message id, and stores
+// it, ctx carries the span of the Update.
+func (s *service) update(ctx context.Context, id ulid.ULID, value string, revision int32, client string) error {
+	m, err := s.ms.Get(id)
+	if err != nil {
+		return err
+	}
+	// the revision and the status are checked before the approval so the
+	// backend is not called in vain, UpdateContent checks them again.
+	if m.Revision != revision {
+		return fmt.Errorf("message %s is at revision %d, not %d: %w", id, m.Revision, revision, store.ErrConflict)
+	}
+	if err := message.CheckUpdate(m.Status); err != nil {
+		return err
+	}
+	if err := content.Check(m.ContentType, value); err != nil {
+		return &invalidArgument{"content", err}
+	}
+
+	if err := s.approveUpdate(ctx, m, value); err != nil {
+		return err
+	}
+
+	rev, err := s.ms.UpdateContent(id, value, revision, client)
 	if err != nil {
 		return err
 	}
+	s.log.Debug("content updated", "id", id, "revision", rev)
 
 	return nil
 }
 
 // Cancel ...
-func (s *service) Cancel(id ulid.ULID) error {
-	ok, err := s.pq.DeleteByID(id)
+func (s *service) Cancel(id ulid.ULID, revision int32, client string) error {
+	// the status is changed first, so a message that is already being
+	// delivered cannot be cancelled.
+	msg, err := s.ms.Get(id)
 	if err != nil {
 		return err
 	}
+	if msg.Revision != revision {
+		return fmt.Errorf("message %s is at revision %d, not %d: %w", id, msg.Revision, revision, store.ErrConflict)
+	}
 
-	if !ok {
-		log.Printf("%s not found in priority queue", id)
-		return nil
+	t := message.Transition{
+		To:     message.Cancelled,
+		Source: message.SourceAPI,
+		Client: client,
+	}
+	if err := s.ms.UpdateStatusAt(id, revision, t); err != nil {
+		return err
 	}
+	s.count(msg, t.To)
 
-	err = s.ms.UpdateStatus(id, message.Cancelled)
+	ok, err := s.pq.Delete(id)
 	if err != nil {
 		return err
 	}
 
+	if !ok {
+		s.log.Warn("message not found in priority queue", "id", id)
+	}
+
+	return nil
+}
+
+// GetHistory ...
+func (s *service) GetHistory(id ulid.ULID) ([]*message.Transition, error) {
+	history, err := s.ms.GetHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetVersions ...
+func (s *service) GetVersions(id ulid.ULID) ([]*message.Version, error) {
+	versions, err := s.ms.GetVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Export ...
+func (s *service) Export(f archive.Filter, format string) ([]string, int, error) {
+	if s.exporter == nil {
+		return nil, 0, fmt.Errorf("archive export is %w", ErrNotConfigured)
+	}
+
+	e := *s.exporter
+	if format != "" {
+		e.Format = format
+	}
+
+	return e.Export(f)
+}
+
+// Backup ...
+func (s *service) Backup(w io.Writer) error {
+	return backup.Write(w, s.ms.Dst, s.pq, s.cs, s.tenants)
+}
+
+// GetChannel ...
+func (s *service) GetChannel(name string) (*channel.Channel, error) {
+	if s.cs == nil {
+		return nil, fmt.Errorf("channel store is %w", ErrNotConfigured)
+	}
+
+	return s.cs.Get(name)
+}
+
+// CreateTemplate ...
+func (s *service) CreateTemplate(t templates.Template) (*templates.Template, error) {
+	if s.ts == nil {
+		return nil, fmt.Errorf("template store is %w", ErrNotConfigured)
+	}
+
+	locale, err := templates.ParseLocale(t.Locale)
+	if err != nil {
+		return nil, &invalidArgument{"locale", err}
+	}
+	t.Locale = locale
+
+	if err := t.Validate(); err != nil {
+		return nil, &invalidArgument{"template", err}
+	}
+
+	if s.cs != nil {
+		if _, err := s.cs.Get(t.Channel); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.ts.Create(t)
+}
+
+// GetTemplate ...
+func (s *service) GetTemplate(channel, name, locale string, version int32) (*templates.Template, error) {
+	if s.ts == nil {
+		return nil, fmt.Errorf("template store is %w", ErrNotConfigured)
+	}
+
+	locale, err := templates.ParseLocale(locale)
+	if err != nil {
+		return nil, &invalidArgument{"locale", err}
+	}
+
+	return s.ts.Get(channel, name, locale, version)
+}
+
+// GetTemplateVersions ...
+func (s *service) GetTemplateVersions(channel, name, locale string) ([]*templates.Template, error) {
+	if s.ts == nil {
+		return nil, fmt.Errorf("template store is %w", ErrNotConfigured)
+	}
+
+	locale, err := templates.ParseLocale(locale)
+	if err != nil {
+		return nil, &invalidArgument{"locale", err}
+	}
+
+	return s.ts.Versions(channel, name, locale)
+}
+
+// RenderTemplate ...
+func (s *service) RenderTemplate(channel, name, locale string, version int32, vars map[string]interface{}) (*templates.Template, string, error) {
+	if s.ts == nil {
+		return nil, "", fmt.Errorf("template store is %w", ErrNotConfigured)
+	}
+
+	def := s.defaultLocale
+	if def == "" {
+		def = templates.DefaultLocale
+	}
+	locales, err := templates.Fallbacks(locale, def)
+	if err != nil {
+		return nil, "", &invalidArgument{"locale", err}
+	}
+
+	t, err := s.ts.Resolve(channel, name, locales, version)
+	if err != nil {
+		return nil, "", err
+	}
+	s.log.Debug("template resolved", "channel", channel, "template", name, "locale", locale, "variant", t.Locale, "version", t.Version)
+
+	c, err := t.Render(vars)
+	if err != nil {
+		return nil, "", &invalidArgument{"variables", err}
+	}
+
+	return t, c, nil
+}
+
+// RollbackTemplate ...
+func (s *service) RollbackTemplate(channel, name, locale string, version int32, client string) (*templates.Template, error) {
+	if s.ts == nil {
+		return nil, fmt.Errorf("template store is %w", ErrNotConfigured)
+	}
+
+	if version <= 0 {
+		return nil, &invalidArgument{"version", errors.New("must be positive")}
+	}
+	locale, err := templates.ParseLocale(locale)
+	if err != nil {
+		return nil, &invalidArgument{"locale", err}
+	}
+
+	return s.ts.Rollback(channel, name, locale, version, client)
+}
+
+// Alive ...
+func (s *service) Alive() error {
+	last := time.Unix(0, atomic.LoadInt64(&s.beat))
+	if d := time.Since(last); d > 3*heartbeat {
+		return errors.Errorf("run loop stalled for %s", d.Round(time.Second))
+	}
+
 	return nil
 }
 
+// CheckBackends ...
+func (s *service) CheckBackends(ctx context.Context) map[string]error {
+	if s.cs == nil || s.backends == nil {
+		return nil
+	}
+
+	channels, err := s.cs.GetAll()
+	if err != nil {
+		s.log.Error("could not get channels", "error", err)
+		return nil
+	}
+
+	errs := make(map[string]error, len(channels))
+	for _, ch := range channels {
+		b, err := s.backends.Get(ch.Address())
+		if err != nil {
+			errs[ch.Name] = err
+			continue
+		}
+		errs[ch.Name] = b.Check(ctx)
+	}
+
+	return errs
+}
+
 // Register ...
 func (s *service) Register(c channel.Channel) error {
+	if err := channel.CheckName(c.Name); err != nil {
+		return &invalidArgument{"name", err}
+	}
+	if s.cs == nil {
+		return fmt.Errorf("channel store is %w", ErrNotConfigured)
+	}
+
 	err := s.cs.Register(c)
 	if err != nil {
 		return err
@@ -181,13 +643,21 @@ func (s *service) run() {
 	var next uint64
 	var timer *time.Timer
 
+	beats := time.NewTicker(heartbeat)
+	defer beats.Stop()
+
 	pq := s.pq
 	for {
+		atomic.StoreInt64(&s.beat, time.Now().UnixNano())
+
 		var tick <-chan time.Time
 
-		top := pq.Peek()
+		top, due, err := pq.Peek()
+		if err != nil {
+			s.log.Error("could not peek priority queue", "error", err)
+		}
 		if top != nil {
-			if t := top.Time(); t < next || next == 0 {
+			if t := ulid.Timestamp(due); t < next || next == 0 {
 				var delay int64
 				now := ulid.Timestamp(time.Now())
 				if t >= now {
@@ -216,43 +686,100 @@ func (s *service) run() {
 		case <-tick:
 			id, err := pq.Pop()
 			if err != nil {
-				log.Printf(err.Error())
+				s.log.Error("could not pop priority queue", "error", err)
 			}
 
 			if id != nil {
 				go s.send(*id)
 			}
 			next = 0
-		case id := <-s.idc:
-			pq.Push(id)
+		case <-s.wake:
+		case <-beats.C:
+		}
+	}
+}
+
+// sweep applies the retention policy every p.Interval, it runs in its
+// goroutine.
+func (s *service) sweep(p *message.RetentionPolicy) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		n, err := s.ms.Compact(p, now)
+		if err != nil {
+			s.log.Error("could not apply retention policy", "error", err)
+			continue
+		}
+
+		if n > 0 {
+			s.log.Info("retention policy applied", "messages", n)
 		}
 	}
 }
 
 func (s *service) send(id ulid.ULID) {
+	inFlight := metrics.InFlight.WithLabelValues(s.tenant)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
 	msg, err := s.Get(id)
 	if err != nil {
-		log.Printf("Error: could not get message %s, %v", id, err)
+		s.log.Error("could not get message", "id", id, "error", err)
 		return
 	}
 
-	// ch, err := s.cs.Get(msg.Channel)
-	// if err != nil {
-	// 	log.Printf("Error: could not get channel, backend %s is not register, %v", msg.Channel, err)
-	// 	return
-	// }
+	attempt, err := s.attempt(id)
+	if err != nil {
+		s.log.Error("could not get history of message", "id", id, "error", err)
+		return
+	}
 
-	// log.Println(ch.Name)
+	// the delivery runs on its own trace, linked to the one of the Put, as
+	// it may happen long after the Put ended.
+	ctx, span := tracing.Start(context.Background(), "scheduler.send",
+		tracing.Message(msg.ID.String(), msg.Channel, msg.Provider),
+		tracing.LinkTo(msg.TraceContext),
+		trace.WithAttributes(attribute.Int("message.attempt", int(attempt))),
+	)
+	defer span.End()
 
-	err = s.delivery(msg.Content)
+	err = s.updateStatus(msg, message.Transition{
+		To:      message.Sending,
+		Source:  message.SourceScheduler,
+		Attempt: attempt,
+	})
 	if err != nil {
-		log.Printf("Error: failed to deliver message %s, %v", msg.ID, err)
+		s.log.Error("could not start delivery of message", "id", id, "error", err)
+		tracing.Record(span, err)
+		return
+	}
+
+	err = s.deliverContent(ctx, msg.Channel, msg.Provider, msg.Content)
+	if err != nil {
+		tracing.Record(span, err)
+		s.log.Warn("failed to deliver message", "id", msg.ID, "channel", msg.Channel, "provider", msg.Provider, "attempt", attempt, "error", err)
 
 		// update status to failed-deliver
-		e := s.ms.UpdateStatus(id, message.FailedDeliver)
+		e := s.updateStatus(msg, message.Transition{
+			To:      message.FailedDeliver,
+			Source:  message.SourceScheduler,
+			Error:   err.Error(),
+			Attempt: attempt,
+		})
 		if e != nil {
 			// TODO(ca): check this error
-			log.Printf("Error: could not update message status %s, %v", msg.ID, err)
+			s.log.Error("could not update message status", "id", msg.ID, "error", e)
+			return
+		}
+
+		if err := s.scheduleRetry(id, attempt); err != nil {
+			s.log.Error("could not push message retry to priority queue", "id", id, "error", err)
 			return
 		}
 
@@ -260,11 +787,201 @@ func (s *service) send(id ulid.ULID) {
 		return
 	}
 
-	e := s.ms.UpdateStatus(id, message.Sent)
+	e := s.updateStatus(msg, message.Transition{
+		To:      message.Sent,
+		Source:  message.SourceScheduler,
+		Attempt: attempt,
+	})
 	if e != nil {
-		log.Printf("Error: could not update message status %s, %v", msg.ID, err)
+		s.log.Error("could not update message status", "id", msg.ID, "error", e)
 		return
 	}
+
+	lateness := time.Since(ulid.Time(id.Time()))
+	channel, _ := s.labels(msg.Channel, "")
+	metrics.Lateness.WithLabelValues(s.tenant, channel).Observe(lateness.Seconds())
+}
+
+// updateStatus changes the status of the message m and counts the
+// transition.
+func (s *service) updateStatus(m *message.Message, t message.Transition) error {
+	if err := s.ms.UpdateStatus(m.ID, t); err != nil {
+		return err
+	}
+	s.count(m, t.To)
+
+	return nil
+}
+
+// count counts the transition of the message m to status.
+func (s *service) count(m *message.Message, status string) {
+	channel, provider := s.labels(m.Channel, m.Provider)
+	metrics.Transitions.WithLabelValues(s.tenant, channel, provider, status).Inc()
+}
+
+// labels returns the metric labels of the channel name and its provider,
+// which are metrics.Unknown when they are not registered.
+func (s *service) labels(name, provider string) (string, string) {
+	s.channelsMu.Lock()
+	c, ok := s.channels[name]
+	s.channelsMu.Unlock()
+	if !ok {
+		var err error
+		if s.cs == nil {
+			return metrics.Unknown, metrics.Unknown
+		}
+		if c, err = s.channel(name); err != nil {
+			return metrics.Unknown, metrics.Unknown
+		}
+	}
+	if provider != "" && c.Provider(provider) == nil {
+		provider = metrics.Unknown
+	}
+
+	return name, provider
+}
+
+// approveContent approves the content with the Approve func of the config
+// or, if nil, with the backend of the channel.
+func (s *service) approveContent(ctx context.Context, channel, content string) (bool, error) {
+	if s.approve != nil {
+		return s.approve(content)
+	}
+
+	_, b, err := s.backend(channel)
+	if err != nil {
+		return false, err
+	}
+
+	start := time.Now()
+	ok, err := b.ApproveContext(ctx, content)
+	metrics.ObserveBackend(channel, "approve", start, err)
+
+	return ok, err
+}
+
+// deliverContent delivers the content with the Delivery func of the config
+// or, if nil, with the backend of the channel. The params of the provider
+// are resolved here, so the secrets are only read to be delivered.
+func (s *service) deliverContent(ctx context.Context, channel, provider, content string) error {
+	if s.delivery != nil {
+		return s.delivery(content)
+	}
+
+	ch, b, err := s.backend(channel)
+	if err != nil {
+		return err
+	}
+
+	p := ch.Provider(provider)
+	if p == nil {
+		start := time.Now()
+		err := b.DeliverContext(ctx, content)
+		metrics.ObserveBackend(channel, "deliver", start, err)
+
+		return err
+	}
+
+	params, err := s.secrets.ResolveParams(p.Params)
+	if err != nil {
+		return errors.Wrapf(err, "provider %s", provider)
+	}
+
+	start := time.Now()
+	err = b.DeliverProviderContext(ctx, content, provider, params)
+	metrics.ObserveBackend(channel, "deliver", start, err)
+
+	return err
+}
+
+// backend returns the channel and the client of its backend.
+func (s *service) backend(name string) (*channel.Channel, *backend.Client, error) {
+	if s.cs == nil || s.backends == nil {
+		return nil, nil, fmt.Errorf("channel backends are %w", ErrNotConfigured)
+	}
+
+	ch, err := s.channel(name)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not get channel %s", name)
+	}
+
+	b, err := s.backends.Get(ch.Address())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ch, b, nil
+}
+
+// channel returns the channel with the given name from the channel store
+// or, while it is unavailable, the last one read from it.
+func (s *service) channel(name string) (*channel.Channel, error) {
+	c, err := s.cs.Get(name)
+
+	s.channelsMu.Lock()
+	defer s.channelsMu.Unlock()
+
+	switch {
+	case err == nil:
+		s.channels[name] = c
+		return c, nil
+	case errors.Is(err, store.ErrNotFound):
+		delete(s.channels, name)
+		return nil, err
+	}
+
+	cached, ok := s.channels[name]
+	if !ok {
+		return nil, err
+	}
+	s.log.Warn("channel store unavailable, using the cached channel", "channel", name, "error", err)
+
+	return cached, nil
+}
+
+// enqueue pushes id to the priority queue and wakes the run loop. Unless
+// max is 0 it fails with ErrQuotaExceeded when the queue holds max ids.
+func (s *service) enqueue(id ulid.ULID, max int) error {
+	var err error
+	if max > 0 {
+		err = s.pq.PushMax(id, max)
+	} else {
+		err = s.pq.Push(id)
+	}
+	if errors.Is(err, queue.ErrFull) {
+		return ErrQuotaExceeded
+	}
+	if err != nil {
+		return err
+	}
+	s.notify()
+
+	return nil
+}
+
+// notify wakes the run loop, unless it is already to be woken.
+func (s *service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// attempt returns the number of the next delivery attempt of the message.
+func (s *service) attempt(id ulid.ULID) (int32, error) {
+	history, err := s.ms.GetHistory(id)
+	if err != nil {
+		return 0, err
+	}
+
+	var attempt int32 = 1
+	for _, t := range history {
+		if t.To == message.Sending {
+			attempt++
+		}
+	}
+
+	return attempt, nil
 }
 
 // TODO(ca): move this to other site.
@@ -278,7 +995,7 @@ func generateID(criteriaDelay time.Duration) (*ulid.ULID, error) {
 	)
 	if err != nil {
 		//TODO: move this message
-		log.Printf("Failed to create message id - %v", err)
+		slog.Error("failed to create message id", "error", err)
 		return nil, err
 	}
 
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microapis/messages-core/channel"
+	"github.com/microapis/messages-core/message"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+
+	db "github.com/microapis/messages-core/message/database"
+	dbBolt "github.com/microapis/messages-core/message/database/bolt"
+	queueBolt "github.com/microapis/messages-core/queue/bolt"
+)
+
+// newService returns a service backed by bolt, without channel store, which
+// approves every content. Its run loop is not started.
+func newService(t *testing.T) *service {
+	t.Helper()
+	dst, err := db.NewBoltDatastore(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dst.DB.Close() })
+
+	ms, err := dbBolt.NewMessageStore(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pq, err := queueBolt.NewQueue(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &service{
+		pq:         pq,
+		ms:         ms,
+		wake:       make(chan struct{}, 1),
+		channels:   make(map[string]*channel.Channel),
+		validators: make(map[string]*compiled),
+		log:        slog.Default(),
+		approve:    func(string) (bool, error) { return true, nil },
+		approving:  make(map[ulid.ULID]bool),
+	}
+}
+
+func newMessage(channel, content string) message.Message {
+	return message.Message{
+		ID:      ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader),
+		Channel: channel,
+		Content: content,
+	}
+}
+
+func TestPutQuota(t *testing.T) {
+	s := newService(t)
+	s.maxQueued = 3
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.Put(newMessage("email", "content"))
+		}(i)
+	}
+	wg.Wait()
+
+	var exceeded int
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, ErrQuotaExceeded):
+			exceeded++
+		case err != nil:
+			t.Errorf("Put() = %v, want nil or %v", err, ErrQuotaExceeded)
+		}
+	}
+	if exceeded != n-s.maxQueued {
+		t.Errorf("Put() exceeded the quota %d times, want %d", exceeded, n-s.maxQueued)
+	}
+	if l, err := s.pq.Len(); err != nil || l != s.maxQueued {
+		t.Errorf("Len() = %d, %v, want %d", l, err, s.maxQueued)
+	}
+}
+
+func TestRegisterName(t *testing.T) {
+	s := newService(t)
+
+	var ia *invalidArgument
+	if err := s.Register(channel.Channel{Name: "billing:email"}); !errors.As(err, &ia) || ia.field != "name" {
+		t.Errorf("Register() of a name with \":\" = %v, want an invalid name", err)
+	}
+	if err := s.Register(channel.Channel{Name: "email"}); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Register() = %v, want %v", err, ErrNotConfigured)
+	}
+}
+
+func TestValidatorCache(t *testing.T) {
+	s := newService(t)
+	schema := &channel.Schema{JSON: json.RawMessage(`{"type":"object"}`)}
+
+	first, err := s.validator("email", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a channel read again with the same schema reuses the validator.
+	again, err := s.validator("email", &channel.Schema{JSON: json.RawMessage(`{"type":"object"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("validator() of the same schema compiled it again")
+	}
+
+	changed, err := s.validator("email", &channel.Schema{JSON: json.RawMessage(`{"type":"string"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == first {
+		t.Error("validator() of a changed schema returned the former validator")
+	}
+	if err := changed.Validate("application/json", `{}`); err == nil {
+		t.Error("Validate() with the changed schema did not fail")
+	}
+
+	if _, err := s.validator("sms", &channel.Schema{}); err == nil {
+		t.Error("validator() of an invalid schema did not fail")
+	}
+}
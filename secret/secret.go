@@ -0,0 +1,200 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Schemes of the secret references.
+const (
+	// Secret references a secret of the secret store, as in
+	// secret://sendgrid/api_key.
+	Secret = "secret"
+	// Env references an environment variable, as in env://SENDGRID_API_KEY.
+	Env = "env"
+	// File references a file, as in file:///run/secrets/sendgrid.
+	File = "file"
+)
+
+// Redacted replaces the secret values in logs and responses.
+const Redacted = "[redacted]"
+
+// Resolver returns the value of the references of a scheme.
+type Resolver interface {
+	// Resolve returns the value of ref, which is the reference without
+	// its scheme.
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc ...
+type ResolverFunc func(ref string) (string, error)
+
+// Resolve ...
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// Resolvers are the resolvers by scheme.
+type Resolvers map[string]Resolver
+
+// Config selects the references that can be resolved. The references are
+// set by the clients that register the channels, and their values are sent
+// to the backend of the channel, so every scheme is disabled unless it is
+// configured.
+type Config struct {
+	// Dir resolves secret://name/key from the file Dir/name/key, as the
+	// secrets mounted by kubernetes or docker.
+	Dir string
+
+	// Env are the names of the environment variables that env://NAME
+	// may resolve.
+	Env []string
+
+	// FileDir is the directory of the files that file:///path may
+	// resolve, including its subdirectories.
+	FileDir string
+}
+
+// NewResolvers returns the resolvers enabled by c, a scheme without
+// resolver fails to resolve.
+func NewResolvers(c Config) Resolvers {
+	r := make(Resolvers)
+	if c.Dir != "" {
+		r[Secret] = ResolverFunc(func(ref string) (string, error) {
+			name := filepath.Clean("/" + ref)
+			return resolveFile(filepath.Join(c.Dir, name))
+		})
+	}
+	if len(c.Env) > 0 {
+		allowed := make(map[string]bool, len(c.Env))
+		for _, name := range c.Env {
+			allowed[name] = true
+		}
+		r[Env] = ResolverFunc(func(name string) (string, error) {
+			if !allowed[name] {
+				return "", errors.New("environment variable not allowed")
+			}
+			return resolveEnv(name)
+		})
+	}
+	if c.FileDir != "" {
+		r[File] = ResolverFunc(func(path string) (string, error) {
+			path = filepath.Clean(path)
+			rel, err := filepath.Rel(c.FileDir, path)
+			if err != nil || !filepath.IsAbs(path) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return "", errors.New("file not allowed")
+			}
+			return resolveFile(path)
+		})
+	}
+
+	return r
+}
+
+// Parse splits a reference on its scheme and ref, reporting whether v is
+// a reference.
+func Parse(v string) (scheme, ref string, ok bool) {
+	i := strings.Index(v, "://")
+	if i < 0 {
+		return "", "", false
+	}
+
+	switch scheme = v[:i]; scheme {
+	case Secret, Env, File:
+		return scheme, v[i+3:], true
+	}
+
+	return "", "", false
+}
+
+// IsRef reports whether v is a secret reference.
+func IsRef(v string) bool {
+	_, _, ok := Parse(v)
+	return ok
+}
+
+// Resolve returns the value referenced by v, or v if it is not a
+// reference.
+func (r Resolvers) Resolve(v string) (string, error) {
+	scheme, ref, ok := Parse(v)
+	if !ok {
+		return v, nil
+	}
+
+	res, ok := r[scheme]
+	if !ok {
+		return "", fmt.Errorf("no resolver for %s references", scheme)
+	}
+
+	s, err := res.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %s: %v", v, err)
+	}
+
+	return s, nil
+}
+
+// ResolveParams returns a copy of params with the references resolved.
+func (r Resolvers) ResolveParams(params map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(params))
+	for k, v := range params {
+		s, err := r.Resolve(v)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %v", k, err)
+		}
+		resolved[k] = s
+	}
+
+	return resolved, nil
+}
+
+// sensitive are the words of the param names holding secrets.
+var sensitive = []string{"key", "secret", "token", "password", "passwd", "auth", "credential"}
+
+// Redact returns a copy of params without secret values. The references
+// are kept, as they do not hold the secret, and the plaintext values of
+// the params named like a secret are replaced by Redacted.
+func Redact(params map[string]string) map[string]string {
+	if params == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(params))
+	for k, v := range params {
+		redacted[k] = v
+		if IsRef(v) || v == "" {
+			continue
+		}
+
+		name := strings.ToLower(k)
+		for _, s := range sensitive {
+			if strings.Contains(name, s) {
+				redacted[k] = Redacted
+				break
+			}
+		}
+	}
+
+	return redacted
+}
+
+func resolveEnv(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", errors.New("environment variable not set")
+	}
+
+	return v, nil
+}
+
+func resolveFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
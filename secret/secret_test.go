@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvers(t *testing.T) {
+	dir := t.TempDir()
+	secrets := filepath.Join(dir, "secrets")
+	files := filepath.Join(dir, "files")
+	for path, v := range map[string]string{
+		filepath.Join(secrets, "sendgrid", "api_key"): "sg\n",
+		filepath.Join(files, "twilio", "token"):       "tw",
+		filepath.Join(dir, "keyfile.json"):            "keys",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(v), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("SENDGRID_API_KEY", "env-sg")
+	t.Setenv("JWT_SECRET", "jwt")
+
+	r := NewResolvers(Config{Dir: secrets, Env: []string{"SENDGRID_API_KEY"}, FileDir: files})
+	disabled := NewResolvers(Config{})
+
+	tests := []struct {
+		r    Resolvers
+		ref  string
+		want string
+		ok   bool
+	}{
+		{r, "plain", "plain", true},
+		{r, "secret://sendgrid/api_key", "sg", true},
+		{r, "secret://../keyfile.json", "", false},
+		{r, "env://SENDGRID_API_KEY", "env-sg", true},
+		{r, "env://JWT_SECRET", "", false},
+		{r, "file://" + filepath.Join(files, "twilio", "token"), "tw", true},
+		{r, "file://" + filepath.Join(files, "..", "keyfile.json"), "", false},
+		{r, "file://" + filepath.Join(dir, "keyfile.json"), "", false},
+		{r, "file://" + files + "-other/token", "", false},
+		{r, "file://twilio/token", "", false},
+		{disabled, "secret://sendgrid/api_key", "", false},
+		{disabled, "env://SENDGRID_API_KEY", "", false},
+		{disabled, "file://" + filepath.Join(files, "twilio", "token"), "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.r.Resolve(tt.ref)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, ok %v", tt.ref, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := Redact(map[string]string{
+		"api_key": "plain",
+		"token":   "env://TOKEN",
+		"from":    "noreply@example.com",
+		"secret":  "",
+	})
+	want := map[string]string{
+		"api_key": Redacted,
+		"token":   "env://TOKEN",
+		"from":    "noreply@example.com",
+		"secret":  "",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Redact()[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
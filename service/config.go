@@ -0,0 +1,587 @@
+package service
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/microapis/messages-core/archive"
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/logger"
+	"github.com/microapis/messages-core/message"
+	"github.com/microapis/messages-core/queue"
+	"github.com/microapis/messages-core/scheduler"
+	"github.com/microapis/messages-core/secret"
+	"github.com/microapis/messages-core/templates"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/microapis/messages-core/tlsconfig"
+	"github.com/microapis/messages-core/tracing"
+	"gopkg.in/yaml.v3"
+
+	queueredis "github.com/microapis/messages-core/queue/redis"
+)
+
+// Log levels ...
+const (
+	// LogDebug ...
+	LogDebug = "debug"
+	// LogInfo ...
+	LogInfo = "info"
+	// LogWarn ...
+	LogWarn = "warn"
+	// LogError ...
+	LogError = "error"
+)
+
+// ServiceConfig ...
+type ServiceConfig struct {
+	// Addr is the address where the gRPC server listens.
+	Addr string `yaml:"addr" toml:"addr" json:"addr"`
+
+	// MetricsAddr is the address where the prometheus metrics are served
+	// on /metrics, empty disables them.
+	MetricsAddr string `yaml:"metrics_addr" toml:"metrics_addr" json:"metrics_addr"`
+
+	// LegacyErrors returns the errors of the RPCs on their error field
+	// with an OK status, for the clients that do not handle the status
+	// codes, which are sent on the trailers instead.
+	LegacyErrors bool `yaml:"legacy_errors" toml:"legacy_errors" json:"legacy_errors"`
+
+	// HealthInterval is the interval of the health checks reported on
+	// the grpc.health.v1 service.
+	HealthInterval time.Duration `yaml:"health_interval" toml:"health_interval" json:"health_interval"`
+
+	DB    DBConfig    `yaml:"db" toml:"db" json:"db"`
+	Redis RedisConfig `yaml:"redis" toml:"redis" json:"redis"`
+
+	// RedisURL is the url of the redis server.
+	//
+	// Deprecated: use Redis.URL, RedisURL is used only when it is empty.
+	RedisURL string `yaml:"-" toml:"-" json:"-"`
+
+	// QueueDriver selects the priority queue implementation, queue.Redis
+	// by default or queue.Bolt to keep the queue in the messages db.
+	QueueDriver string `yaml:"queue_driver" toml:"queue_driver" json:"queue_driver"`
+
+	// QueueBuffer is the number of messages kept in memory while the redis
+	// queue is unavailable, 0 fails the puts instead.
+	QueueBuffer int `yaml:"queue_buffer" toml:"queue_buffer" json:"queue_buffer"`
+
+	// Approval runs the approval of the messages received by Put inline
+	// (default), answering with its outcome, or async, answering once the
+	// message is stored.
+	Approval string `yaml:"approval" toml:"approval" json:"approval"`
+
+	// DefaultLocale is the locale of the variants of the templates used
+	// when none matches the locale of the recipient.
+	DefaultLocale string `yaml:"default_locale" toml:"default_locale" json:"default_locale"`
+
+	// Retry is the policy applied when a delivery fails.
+	Retry scheduler.RetryPolicy `yaml:"retry" toml:"retry" json:"retry"`
+
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit" json:"rate_limit"`
+
+	// TLS secures the gRPC server and the clients of the channel backends.
+	TLS tlsconfig.Config `yaml:"tls" toml:"tls" json:"tls"`
+
+	// Auth authenticates the callers of the gRPC server, it is disabled
+	// when no api key nor jwt is configured.
+	Auth auth.Config `yaml:"auth" toml:"auth" json:"auth"`
+
+	// Tenants are served besides the default tenant, each one with its
+	// own messages, queue, channels and limits.
+	Tenants []tenant.Config `yaml:"tenants" toml:"tenants" json:"tenants"`
+
+	// LogLevel is one of debug, info (default), warn or error.
+	LogLevel string `yaml:"log_level" toml:"log_level" json:"log_level"`
+	// LogFormat is text (default) or json.
+	LogFormat string `yaml:"log_format" toml:"log_format" json:"log_format"`
+	// LogRedaction masks the content of the messages and the params of the
+	// providers on the logs.
+	LogRedaction logger.Redaction `yaml:"log_redaction" toml:"log_redaction" json:"log_redaction"`
+
+	// Tracing exports the OpenTelemetry spans of the service.
+	Tracing tracing.Config `yaml:"tracing" toml:"tracing" json:"tracing"`
+
+	// Retention is the policy applied over the delivered messages, nil
+	// keeps the messages forever.
+	Retention *message.RetentionPolicy `yaml:"retention" toml:"retention" json:"retention"`
+
+	Archive ArchiveConfig `yaml:"archive" toml:"archive" json:"archive"`
+
+	Encryption EncryptionConfig `yaml:"encryption" toml:"encryption" json:"encryption"`
+
+	Secrets SecretsConfig `yaml:"secrets" toml:"secrets" json:"secrets"`
+
+	Approve func(content string) (bool, error) `yaml:"-" toml:"-" json:"-"`
+	Deliver func(content string) error         `yaml:"-" toml:"-" json:"-"`
+}
+
+// DBConfig ...
+type DBConfig struct {
+	// Driver is the message store implementation, only bolt for now. Empty
+	// is bolt.
+	Driver string `yaml:"driver" toml:"driver" json:"driver"`
+	// Path is the path of the messages db, DefaultDBPath when empty.
+	Path string `yaml:"path" toml:"path" json:"path"`
+}
+
+// DefaultDBPath is the path of the messages db when none is configured.
+const DefaultDBPath = "messages.db"
+
+// RedisConfig ...
+type RedisConfig struct {
+	// URL of the redis server used by the channel registry and the redis
+	// priority queue, empty disables the channel registry.
+	URL string `yaml:"url" toml:"url" json:"url"`
+
+	queueredis.PoolConfig `yaml:",inline"`
+}
+
+// RateLimitConfig limits the requests accepted by the gRPC server from
+// each client, or from each tenant when the authentication is disabled.
+type RateLimitConfig struct {
+	// Rate is the number of requests per second, 0 disables the limit.
+	Rate float64 `yaml:"rate" toml:"rate" json:"rate"`
+	// Burst is the number of requests accepted over the rate at once.
+	Burst int `yaml:"burst" toml:"burst" json:"burst"`
+}
+
+// ArchiveConfig ...
+type ArchiveConfig struct {
+	// URL is the destination of the exported messages, a local directory
+	// or an s3://bucket/prefix url. Empty disables the exports.
+	URL string `yaml:"url" toml:"url" json:"url"`
+	// Format is archive.NDJSON (default) or archive.Parquet.
+	Format string `yaml:"format" toml:"format" json:"format"`
+	// MaxRecords is the number of messages written on each archive file,
+	// 0 writes a single file per export.
+	MaxRecords int `yaml:"max_records" toml:"max_records" json:"max_records"`
+}
+
+// EncryptionConfig ...
+type EncryptionConfig struct {
+	// Keyfile is the JSON keyfile of the keys that encrypt the content of
+	// the messages and the params of the providers. Empty stores them in
+	// plaintext.
+	Keyfile string `yaml:"keyfile" toml:"keyfile" json:"keyfile"`
+}
+
+// SecretsConfig ...
+type SecretsConfig struct {
+	// Dir resolves the secret://name/key references of the provider
+	// params from the file dir/name/key.
+	Dir string `yaml:"dir" toml:"dir" json:"dir"`
+
+	// Env are the environment variables that the env://NAME references
+	// may read, none by default.
+	Env []string `yaml:"env" toml:"env" json:"env"`
+
+	// FileDir is the directory of the files that the file:///path
+	// references may read, empty disables them.
+	FileDir string `yaml:"file_dir" toml:"file_dir" json:"file_dir"`
+}
+
+// Resolvers returns the config of the secret references.
+func (c SecretsConfig) Resolvers() secret.Config {
+	return secret.Config{
+		Dir:     c.Dir,
+		Env:     c.Env,
+		FileDir: c.FileDir,
+	}
+}
+
+// Logger returns the config of the logger.
+func (c *ServiceConfig) Logger() logger.Config {
+	return logger.Config{
+		Level:     c.LogLevel,
+		Format:    c.LogFormat,
+		Redaction: c.LogRedaction,
+	}
+}
+
+// DefaultConfig returns the configuration used when nothing is set.
+func DefaultConfig() ServiceConfig {
+	return ServiceConfig{
+		Addr:           ":5020",
+		HealthInterval: 10 * time.Second,
+		DB: DBConfig{
+			Driver: "bolt",
+			Path:   DefaultDBPath,
+		},
+		QueueDriver: queue.Redis,
+		QueueBuffer: 10000,
+		Approval:    scheduler.ApprovalInline,
+
+		DefaultLocale: templates.DefaultLocale,
+		Retry: scheduler.RetryPolicy{
+			MaxAttempts: 1,
+		},
+		LogLevel:  LogInfo,
+		LogFormat: logger.Text,
+		LogRedaction: logger.Redaction{
+			Content: logger.Mask,
+			Params:  logger.Secrets,
+		},
+		Archive: ArchiveConfig{
+			Format: archive.NDJSON,
+		},
+	}
+}
+
+// envPrefix is prepended to the names of the environment variables.
+const envPrefix = "MESSAGES_"
+
+// LoadConfig builds the configuration from the defaults, the config file,
+// the environment variables and the command line flags in args, each one
+// overriding the previous.
+//
+// The config file is a .yaml, .yml or .toml file given by the -config flag
+// or the MESSAGES_CONFIG environment variable. Every setting can be set by
+// a MESSAGES_<SECTION>_<NAME> variable or a -<section>-<name> flag, like
+// MESSAGES_REDIS_URL or -redis-url.
+//
+// The returned configuration is validated.
+func LoadConfig(args []string) (*ServiceConfig, error) {
+	c := DefaultConfig()
+
+	flags := make(map[string]string)
+	fs := flag.NewFlagSet("messages", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "path of the yaml or toml config file")
+	for _, s := range c.settings() {
+		name := strings.Replace(s.key, "_", "-", -1)
+		fs.Func(name, s.usage, func(v string) error {
+			flags[name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		if err := c.load(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range c.settings() {
+		env := envPrefix + strings.ToUpper(s.key)
+		if v, ok := os.LookupEnv(env); ok {
+			if err := s.set(v); err != nil {
+				return nil, fmt.Errorf("%s: %v", env, err)
+			}
+		}
+	}
+
+	for _, s := range c.settings() {
+		name := strings.Replace(s.key, "_", "-", -1)
+		if v, ok := flags[name]; ok {
+			if err := s.set(v); err != nil {
+				return nil, fmt.Errorf("-%s: %v", name, err)
+			}
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// load reads the config file on path over c.
+func (c *ServiceConfig) load(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, c)
+	case ".toml":
+		_, err = toml.Decode(string(b), c)
+	default:
+		err = errors.New("unknown config file format, must be yaml or toml")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	return nil
+}
+
+// setting is a value of the config that can be set from the environment
+// or a flag.
+type setting struct {
+	key   string
+	usage string
+	ptr   interface{}
+}
+
+func (s setting) set(v string) error {
+	switch p := s.ptr.(type) {
+	case *string:
+		*p = v
+	case *int:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*p = n
+	case *bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*p = b
+	case *[]string:
+		*p = strings.Split(v, ",")
+	case *float64:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*p = f
+	case *time.Duration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*p = d
+	default:
+		return fmt.Errorf("unsupported setting type %T", s.ptr)
+	}
+
+	return nil
+}
+
+// settings lists the values of c that can be set from the environment or
+// the flags.
+func (c *ServiceConfig) settings() []setting {
+	return []setting{
+		{"addr", "address of the gRPC server", &c.Addr},
+		{"metrics_addr", "address of the prometheus metrics, empty disables them", &c.MetricsAddr},
+		{"health_interval", "interval of the health checks", &c.HealthInterval},
+		{"legacy_errors", "return the errors on the error field of the responses", &c.LegacyErrors},
+		{"db_driver", "driver of the message store", &c.DB.Driver},
+		{"db_path", "path of the messages db", &c.DB.Path},
+		{"redis_url", "url of the redis server", &c.Redis.URL},
+		{"redis_max_idle", "max idle connections of the redis queue", &c.Redis.MaxIdle},
+		{"redis_max_active", "max connections to redis, 0 is unlimited", &c.Redis.MaxActive},
+		{"redis_idle_timeout", "time before closing an idle redis connection", &c.Redis.IdleTimeout},
+		{"queue_driver", "priority queue implementation, redis or bolt", &c.QueueDriver},
+		{"queue_buffer", "messages buffered while the redis queue is unavailable", &c.QueueBuffer},
+		{"approval", "approval of the received messages, inline or async", &c.Approval},
+		{"default_locale", "locale of the templates used when none matches the recipient", &c.DefaultLocale},
+		{"retry_max_attempts", "delivery attempts of a message", &c.Retry.MaxAttempts},
+		{"retry_backoff", "delay before the first delivery retry", &c.Retry.Backoff},
+		{"retry_max_backoff", "max delay between delivery retries", &c.Retry.MaxBackoff},
+		{"rate_limit_rate", "requests per second accepted, 0 disables the limit", &c.RateLimit.Rate},
+		{"rate_limit_burst", "requests accepted over the rate at once", &c.RateLimit.Burst},
+		{"tls_cert_file", "certificate of the gRPC server and the backend clients", &c.TLS.CertFile},
+		{"tls_key_file", "private key of the gRPC server and the backend clients", &c.TLS.KeyFile},
+		{"tls_ca_file", "CA bundle used to verify the clients and the backends", &c.TLS.CAFile},
+		{"tls_client_auth", "require client certificates on the gRPC server", &c.TLS.ClientAuth},
+		{"tls_allowed_names", "comma separated names allowed on the peer certificates", &c.TLS.AllowedNames},
+		{"tls_reload_interval", "how often the TLS files are checked for changes", &c.TLS.ReloadInterval},
+		{"log_level", "debug, info, warn or error", &c.LogLevel},
+		{"log_format", "text or json", &c.LogFormat},
+		{"log_redaction_content", "redaction of the message content on the logs, mask, hash or none", &c.LogRedaction.Content},
+		{"log_redaction_params", "redaction of the provider params on the logs, secrets, mask or none", &c.LogRedaction.Params},
+		{"log_redaction_hash_key", "key of the hmac of the content redacted with hash", &c.LogRedaction.HashKey},
+		{"archive_url", "destination directory or s3 url of the exports", &c.Archive.URL},
+		{"archive_format", "format of the exports, ndjson or parquet", &c.Archive.Format},
+		{"archive_max_records", "messages per export file, 0 writes a single file", &c.Archive.MaxRecords},
+		{"encryption_keyfile", "keyfile of the encryption at rest, empty disables it", &c.Encryption.Keyfile},
+		{"tracing_endpoint", "OTLP gRPC collector of the traces, empty does not export them", &c.Tracing.Endpoint},
+		{"tracing_insecure", "disable TLS on the connection to the trace collector", &c.Tracing.Insecure},
+		{"tracing_sample_ratio", "ratio of the sampled traces, 0 samples them all", &c.Tracing.SampleRatio},
+		{"secrets_dir", "directory of the secret:// references of the provider params", &c.Secrets.Dir},
+		{"secrets_env", "comma separated environment variables allowed on the env:// references", &c.Secrets.Env},
+		{"secrets_file_dir", "directory of the files allowed on the file:// references", &c.Secrets.FileDir},
+	}
+}
+
+// withDefaults returns c with the values left empty by the configs built
+// without LoadConfig, as the ones of the former flat ServiceConfig, set:
+// the deprecated RedisURL is moved to Redis.URL and the messages db is
+// kept on bolt at DefaultDBPath.
+func (c ServiceConfig) withDefaults() ServiceConfig {
+	if c.Redis.URL == "" {
+		c.Redis.URL = c.RedisURL
+	}
+	if c.DB.Driver == "" {
+		c.DB.Driver = "bolt"
+	}
+	if c.DB.Path == "" {
+		c.DB.Path = DefaultDBPath
+	}
+
+	return c
+}
+
+// Validate returns an error describing every invalid value of c.
+func (c *ServiceConfig) Validate() error {
+	d := c.withDefaults()
+	c = &d
+
+	var errs []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Addr != "", "addr is required")
+	check(c.HealthInterval >= 0, "health_interval must not be negative")
+	check(c.DB.Driver == "bolt", "db.driver %q is not supported, must be bolt", c.DB.Driver)
+	check(c.DB.Path != "", "db.path is required")
+
+	if c.Redis.URL != "" {
+		u, err := url.Parse(c.Redis.URL)
+		check(err == nil && (u.Scheme == "redis" || u.Scheme == "rediss"), "redis.url %q must be a redis:// or rediss:// url", c.Redis.URL)
+	}
+	check(c.Redis.MaxIdle >= 0, "redis.max_idle must not be negative")
+	check(c.Redis.MaxActive >= 0, "redis.max_active must not be negative")
+	check(c.Redis.IdleTimeout >= 0, "redis.idle_timeout must not be negative")
+
+	switch c.QueueDriver {
+	case "", queue.Redis:
+		check(c.Redis.URL != "", "redis.url is required by the redis queue driver")
+	case queue.Bolt:
+	default:
+		check(false, "queue_driver %q is not supported, must be redis or bolt", c.QueueDriver)
+	}
+	check(c.QueueBuffer >= 0, "queue_buffer must not be negative")
+
+	switch c.Approval {
+	case "", scheduler.ApprovalInline, scheduler.ApprovalAsync:
+	default:
+		check(false, "approval %q is not supported, must be inline or async", c.Approval)
+	}
+	if _, err := templates.ParseLocale(c.DefaultLocale); err != nil {
+		check(false, "default_locale %q is not a BCP 47 tag: %v", c.DefaultLocale, err)
+	}
+
+	check(c.Retry.MaxAttempts >= 0, "retry.max_attempts must not be negative")
+	check(c.Retry.Backoff >= 0, "retry.backoff must not be negative")
+	check(c.Retry.MaxBackoff >= 0, "retry.max_backoff must not be negative")
+	check(c.Retry.MaxAttempts <= 1 || c.Retry.Backoff > 0, "retry.backoff is required when retry.max_attempts is greater than 1")
+
+	check(c.RateLimit.Rate >= 0, "rate_limit.rate must not be negative")
+	check(c.RateLimit.Rate == 0 || c.RateLimit.Burst > 0, "rate_limit.burst must be positive when rate_limit.rate is set")
+
+	if err := c.TLS.Validate(); err != nil {
+		check(false, "tls.%v", err)
+	}
+	check(c.TLS.CertFile != "" || c.TLS.CAFile == "", "tls.cert_file is required by the gRPC server when tls is enabled")
+
+	check(c.Secrets.FileDir == "" || filepath.IsAbs(c.Secrets.FileDir), "secrets.file_dir %q must be an absolute path", c.Secrets.FileDir)
+
+	if err := c.Auth.Validate(); err != nil {
+		check(false, "auth.%v", err)
+	}
+
+	tenants := make(map[string]bool)
+	for i, t := range c.Tenants {
+		check(!tenants[t.ID], "tenants[%d].id %q is repeated", i, t.ID)
+		check(!tenant.Namespaced(t.ID) && !strings.Contains(t.ID, "/"), "tenants[%d].id %q must not contain ':' or '/'", i, t.ID)
+		check(t.MaxQueued >= 0, "tenants[%d].max_queued must not be negative", i)
+		check(t.Rate >= 0, "tenants[%d].rate must not be negative", i)
+		check(t.Rate == 0 || t.Burst > 0, "tenants[%d].burst must be positive when rate is set", i)
+		tenants[t.ID] = true
+	}
+	for i, k := range c.Auth.APIKeys {
+		check(k.Tenant == tenant.Default || tenants[k.Tenant], "auth.api_keys[%d].tenant %q is not configured", i, k.Tenant)
+	}
+
+	switch c.LogLevel {
+	case "", LogDebug, LogInfo, LogWarn, LogError:
+	default:
+		check(false, "log_level %q is not supported, must be debug, info, warn or error", c.LogLevel)
+	}
+	switch c.LogFormat {
+	case "", logger.Text, logger.JSON:
+	default:
+		check(false, "log_format %q is not supported, must be text or json", c.LogFormat)
+	}
+	if err := c.LogRedaction.Validate(); err != nil {
+		check(false, "log_redaction.%v", err)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	if c.Retention != nil {
+		for i, r := range c.Retention.Rules {
+			switch r.Action {
+			case message.RetentionDelete, message.RetentionArchive, message.RetentionStrip:
+			default:
+				check(false, "retention.rules[%d].action %q must be delete, archive or strip", i, r.Action)
+			}
+			check(r.MaxAge > 0, "retention.rules[%d].max_age must be positive", i)
+		}
+	}
+
+	if c.Archive.URL != "" {
+		_, err := archive.Ext(c.Archive.Format)
+		check(c.Archive.Format == "" || err == nil, "archive.format %q must be ndjson or parquet", c.Archive.Format)
+	}
+	check(c.Archive.MaxRecords >= 0, "archive.max_records must not be negative")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+// Print writes c as yaml to w, hiding the passwords of the urls.
+func (c *ServiceConfig) Print(w io.Writer) error {
+	cc := *c
+	cc.Redis.URL = redactURL(cc.Redis.URL)
+	cc.Archive.URL = redactURL(cc.Archive.URL)
+
+	cc.Auth.APIKeys = make([]auth.APIKey, len(c.Auth.APIKeys))
+	for i, k := range c.Auth.APIKeys {
+		k.Key = "xxxxx"
+		cc.Auth.APIKeys[i] = k
+	}
+	if cc.LogRedaction.HashKey != "" {
+		cc.LogRedaction.HashKey = "xxxxx"
+	}
+	if c.Auth.JWT != nil && c.Auth.JWT.Secret != "" {
+		jwt := *c.Auth.JWT
+		jwt.Secret = "xxxxx"
+		cc.Auth.JWT = &jwt
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&cc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.User == nil {
+		return rawurl
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	return u.String()
+}
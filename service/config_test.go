@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/microapis/messages-core/queue"
+)
+
+func TestValidateDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ServiceConfig
+		ok     bool
+	}{
+		{"default", DefaultConfig(), false},
+		{"default with redis", func() ServiceConfig {
+			c := DefaultConfig()
+			c.Redis.URL = "redis://localhost:6379"
+			return c
+		}(), true},
+		{"zero with deprecated redis url", ServiceConfig{Addr: ":5020", RedisURL: "redis://localhost:6379"}, true},
+		{"zero with bolt queue", ServiceConfig{Addr: ":5020", QueueDriver: queue.Bolt}, true},
+		{"zero without redis", ServiceConfig{Addr: ":5020"}, false},
+		{"unknown driver", ServiceConfig{Addr: ":5020", QueueDriver: queue.Bolt, DB: DBConfig{Driver: "postgres"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	c := ServiceConfig{RedisURL: "redis://legacy:6379"}.withDefaults()
+	if c.Redis.URL != "redis://legacy:6379" {
+		t.Errorf("Redis.URL = %q, want the deprecated RedisURL", c.Redis.URL)
+	}
+	if c.DB.Driver != "bolt" || c.DB.Path != DefaultDBPath {
+		t.Errorf("DB = %+v, want bolt on %s", c.DB, DefaultDBPath)
+	}
+
+	c = ServiceConfig{RedisURL: "redis://legacy:6379", Redis: RedisConfig{URL: "redis://new:6379"}}.withDefaults()
+	if c.Redis.URL != "redis://new:6379" {
+		t.Errorf("Redis.URL = %q, want it kept over the deprecated RedisURL", c.Redis.URL)
+	}
+}
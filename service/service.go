@@ -1,13 +1,25 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"sync"
 
+	"github.com/microapis/messages-core/archive"
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/backend"
+	"github.com/microapis/messages-core/encryption"
+	"github.com/microapis/messages-core/health"
+	"github.com/microapis/messages-core/logger"
+	"github.com/microapis/messages-core/metrics"
 	"github.com/microapis/messages-core/proto"
 	"github.com/microapis/messages-core/scheduler"
 	schedulersvc "github.com/microapis/messages-core/scheduler"
+	"github.com/microapis/messages-core/secret"
+	"github.com/microapis/messages-core/tenant"
+	"github.com/microapis/messages-core/tracing"
 
 	channeldb "github.com/microapis/messages-core/channel/database"
 	"github.com/microapis/messages-core/channel/database/redis"
@@ -15,85 +27,241 @@ import (
 	messagedb "github.com/microapis/messages-core/message/database"
 	"github.com/microapis/messages-core/message/database/bolt"
 
+	"github.com/microapis/messages-core/queue"
+	queuebolt "github.com/microapis/messages-core/queue/bolt"
+	queueredis "github.com/microapis/messages-core/queue/redis"
+
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
-// ServiceConfig ...
-type ServiceConfig struct {
-	Addr string
-
-	RedisURL string
-
-	Approve func(content string) (bool, error)
-	Deliver func(content string) error
-}
-
 // Service ...
 type Service struct {
 	Instance *schedulersvc.Service
 	Name     string
 	Addr     string
+
+	config ServiceConfig
+
+	boltDst  *messagedb.BoltDatastore
+	redisDst *channeldb.RedisDatastore
+	queue    queue.Queue
 }
 
 // NewMessageService ...
 func NewMessageService(name string, config ServiceConfig) (*Service, error) {
-	// ----- Init DB
-	boltDst, err := messagedb.NewBoltDatastore("messages.db")
-	if err != nil {
+	config = config.withDefaults()
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
-	redisDst, err := channeldb.NewRedisDatastore(config.RedisURL)
+	if err := logger.Setup(config.Logger()); err != nil {
+		return nil, err
+	}
+
+	// ----- Init DB
+	boltDst, err := messagedb.NewBoltDatastore(config.DB.Path)
 	if err != nil {
 		return nil, err
 	}
 
+	// initialize encryption at rest
+	var encrypter *encryption.Encrypter
+	if config.Encryption.Keyfile != "" {
+		keys, err := encryption.NewLocalKeyProvider(config.Encryption.Keyfile)
+		if err != nil {
+			return nil, err
+		}
+		encrypter = encryption.New(keys)
+	}
+
 	// initialize message store
 	ms, err := bolt.NewMessageStore(boltDst)
 	if err != nil {
 		return nil, err
 	}
+	ms.Encrypter = encrypter
 
-	// initialize channel store
-	cs, err := redis.NewChannelStore(redisDst)
+	// initialize template store
+	ts, err := bolt.NewTemplateStore(boltDst)
 	if err != nil {
 		return nil, err
 	}
 
-	svc := schedulersvc.NewRPC(scheduler.StorageConfig{
-		MessageStore: ms,
-		ChannelStore: cs,
+	// initialize channel store, only when redis is configured
+	var cs *redis.ChannelStore
+	var redisDst *channeldb.RedisDatastore
+	if config.Redis.URL != "" {
+		redisDst, err = channeldb.NewRedisDatastore(config.Redis.URL, config.Redis.MaxActive)
+		if err != nil {
+			return nil, err
+		}
+
+		cs, err = redis.NewChannelStore(redisDst)
+		if err != nil {
+			return nil, err
+		}
+		cs.Encrypter = encrypter
+	}
 
-		RedisURL: config.RedisURL,
+	// initialize priority queue
+	var pq queue.Queue
+	switch config.QueueDriver {
+	case "", queue.Redis:
+		pq, err = queueredis.NewQueue(config.Redis.URL, config.Redis.PoolConfig)
+		if err == nil && config.QueueBuffer > 0 {
+			pq = queue.NewBuffered(pq, config.QueueBuffer)
+		}
+	case queue.Bolt:
+		pq, err = queuebolt.NewQueue(boltDst)
+	default:
+		err = fmt.Errorf("unknown queue driver %q", config.QueueDriver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// initialize archive exporter
+	var exporter *archive.Exporter
+	if config.Archive.URL != "" {
+		sink, err := archive.NewSink(config.Archive.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		format := config.Archive.Format
+		if format == "" {
+			format = archive.NDJSON
+		}
+
+		exporter = &archive.Exporter{
+			Store:      ms,
+			Sink:       sink,
+			Format:     format,
+			MaxRecords: config.Archive.MaxRecords,
+		}
+	}
+
+	// initialize channel backend clients
+	backends, err := backend.NewClients(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := schedulersvc.NewRPC(scheduler.StorageConfig{
+		MessageStore:  ms,
+		ChannelStore:  cs,
+		TemplateStore: ts,
+		DefaultLocale: config.DefaultLocale,
+		Queue:         pq,
+		Retention:     config.Retention,
+		Exporter:      exporter,
+		Retry:         config.Retry,
+		Approval:      config.Approval,
+		Backends:      backends,
+		Secrets:       secret.NewResolvers(config.Secrets.Resolvers()),
 
 		Approve:  config.Approve,
 		Delivery: config.Deliver,
-	})
+	}, config.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	svc.LegacyErrors = config.LegacyErrors
 
 	return &Service{
 		Instance: svc,
 		Name:     name,
 		Addr:     config.Addr,
+
+		config: config,
+
+		boltDst:  boltDst,
+		redisDst: redisDst,
+		queue:    pq,
 	}, nil
 }
 
 // Run ...
 func (s *Service) Run() error {
 	// initialize gprc server
-	srv := grpc.NewServer()
+	opts := []grpc.ServerOption{}
+
+	if s.config.TLS.Enabled() {
+		creds, err := s.config.TLS.ServerCredentials()
+		if err != nil {
+			return err
+		}
+		defer creds.Close()
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	shutdown, err := tracing.Setup(context.Background(), s.Name, s.config.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdown(context.Background())
+
+	unary := []grpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor()}
+	stream := []grpc.StreamServerInterceptor{tracing.StreamServerInterceptor()}
+
+	if s.config.MetricsAddr != "" {
+		unary = append(unary, metrics.UnaryServerInterceptor())
+		stream = append(stream, metrics.StreamServerInterceptor())
+
+		go func() {
+			slog.Info("serving metrics", "addr", s.config.MetricsAddr)
+			if err := metrics.ListenAndServe(s.config.MetricsAddr); err != nil {
+				slog.Error("could not serve metrics", "error", err)
+			}
+		}()
+	}
+
+	// the requests are authenticated before the rate limit, so it is
+	// applied to the client.
+	if s.config.Auth.Enabled() {
+		a, err := auth.New(s.config.Auth)
+		if err != nil {
+			return err
+		}
+		unary = append(unary, a.UnaryInterceptor())
+		stream = append(stream, a.StreamInterceptor())
+	}
+
+	if s.config.RateLimit.Rate > 0 {
+		l := newLimiters(s.config.RateLimit, s.config.Tenants)
+		unary = append(unary, rateLimit(l))
+		stream = append(stream, rateLimitStream(l))
+	}
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+
+	srv := grpc.NewServer(opts...)
 
 	proto.RegisterSchedulerServiceServer(srv, s.Instance)
 	reflection.Register(srv)
 
-	log.Println("Starting Messages " + s.Name + " service...")
+	monitor := s.monitor()
+	monitor.Register(srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go monitor.Run(ctx)
+
+	slog.Info("starting service", "service", s.Name)
 
 	lis, err := net.Listen("tcp", s.Addr)
 	if err != nil {
 		return err
 	}
 
-	log.Println(fmt.Sprintf("Messages "+s.Name+" service, Listening on: %v", s.Addr))
+	slog.Info("service listening", "service", s.Name, "addr", s.Addr)
 
 	if err := srv.Serve(lis); err != nil {
 		return err
@@ -101,3 +269,106 @@ func (s *Service) Run() error {
 
 	return nil
 }
+
+// monitor returns the health monitor of the stores, the queue and the
+// run loops of the schedulers, which are the only liveness component.
+func (s *Service) monitor() *health.Monitor {
+	m := health.NewMonitor(s.config.HealthInterval)
+
+	m.Add("bolt", func(ctx context.Context) error {
+		return s.boltDst.Ping()
+	}, false)
+	if s.redisDst != nil {
+		m.Add("redis", s.redisDst.Ping, false)
+	}
+	m.Add("queue", func(ctx context.Context) error {
+		err := s.queue.Ping()
+		if b, ok := s.queue.(*queue.Buffered); ok {
+			// the puts are buffered meanwhile, so the service is still
+			// ready.
+			n, berr := b.Buffered()
+			if err == nil {
+				err = berr
+			}
+			if err != nil {
+				return health.Degraded(fmt.Errorf("%d messages buffered: %v", n, err))
+			}
+		}
+		return err
+	}, false)
+	m.Add("scheduler", s.Instance.Alive, true)
+	m.AddChannels(s.Instance.CheckBackends)
+
+	return m
+}
+
+// limiters keeps a rate limiter per authenticated client or, when the
+// authentication is disabled, per tenant.
+type limiters struct {
+	limit   rate.Limit
+	burst   int
+	tenants map[string]bool
+
+	mu sync.Mutex
+	m  map[string]*rate.Limiter
+}
+
+func newLimiters(c RateLimitConfig, tenants []tenant.Config) *limiters {
+	l := &limiters{
+		limit:   rate.Limit(c.Rate),
+		burst:   c.Burst,
+		tenants: make(map[string]bool),
+		m:       make(map[string]*rate.Limiter),
+	}
+	for _, t := range tenants {
+		l.tenants[t.ID] = true
+	}
+
+	return l
+}
+
+// get returns the limiter of the caller of ctx. The unknown tenants share
+// the limiter of the default one, as their requests are rejected anyway.
+func (l *limiters) get(ctx context.Context) *rate.Limiter {
+	key := "tenant:" + tenant.Default
+	if c, ok := auth.FromContext(ctx); ok {
+		key = "client:" + c.ID
+	} else if t := tenant.FromContext(ctx); l.tenants[t] {
+		key = "tenant:" + t
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.m[key]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.m[key] = limiter
+	}
+
+	return limiter
+}
+
+// rateLimit rejects the requests of a caller over the limit with
+// ResourceExhausted, except the health checks.
+func rateLimit(l *limiters) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !health.IsHealthMethod(info.FullMethod) && !l.get(ctx).Allow() {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded on %s", info.FullMethod)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStream is the rateLimit of the streams, the opening of every
+// stream counts as a request.
+func rateLimitStream(l *limiters) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !health.IsHealthMethod(info.FullMethod) && !l.get(ss.Context()).Allow() {
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded on %s", info.FullMethod)
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microapis/messages-core/auth"
+	"github.com/microapis/messages-core/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimiters(t *testing.T) {
+	l := newLimiters(RateLimitConfig{Rate: 1, Burst: 1}, []tenant.Config{{ID: "billing"}})
+
+	client := func(id string) context.Context {
+		return auth.NewContext(context.Background(), &auth.Client{ID: id})
+	}
+	tenantCtx := func(id string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.Header, id))
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		ok   bool
+	}{
+		{"first client", client("a"), true},
+		{"first client again", client("a"), false},
+		{"second client", client("b"), true},
+		{"default tenant", context.Background(), true},
+		{"default tenant again", context.Background(), false},
+		{"tenant", tenantCtx("billing"), true},
+		{"unknown tenant shares the default one", tenantCtx("unknown"), false},
+	}
+
+	for _, tt := range tests {
+		if ok := l.get(tt.ctx).Allow(); ok != tt.ok {
+			t.Errorf("%s: Allow() = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
+
+// serverStream is a grpc.ServerStream with the context of a client.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRateLimitStream(t *testing.T) {
+	interceptor := rateLimitStream(newLimiters(RateLimitConfig{Rate: 1, Burst: 1}, nil))
+	ss := &serverStream{ctx: auth.NewContext(context.Background(), &auth.Client{ID: "a"})}
+	handler := func(interface{}, grpc.ServerStream) error { return nil }
+
+	tests := []struct {
+		method string
+		code   codes.Code
+	}{
+		{"/proto.SchedulerService/Backup", codes.OK},
+		{"/proto.SchedulerService/Backup", codes.ResourceExhausted},
+		{"/grpc.health.v1.Health/Watch", codes.OK},
+	}
+
+	for _, tt := range tests {
+		err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s: code = %v, want %v", tt.method, got, tt.code)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package store
+
+import "errors"
+
+// Errors of the message and channel stores, wrapped with the record they
+// refer to. They are checked with errors.Is.
+var (
+	// ErrNotFound is returned when the record does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when the change conflicts with the current
+	// state of the record, as an existing one with the same key.
+	ErrConflict = errors.New("conflict")
+)
@@ -0,0 +1,202 @@
+// Package templates renders the content of the messages from named and
+// versioned templates of their channel.
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/microapis/messages-core/content"
+	"github.com/microapis/messages-core/proto"
+	"golang.org/x/text/language"
+)
+
+// Engines ...
+const (
+	// Text renders with text/template, the default one.
+	Text = "text"
+	// HTML renders with html/template, which escapes the variables.
+	HTML = "html"
+)
+
+// DefaultLocale is the locale tried after the ones of the recipient when
+// no other is configured.
+const DefaultLocale = "en"
+
+// Template is a version of the template of a channel. The versions are
+// never changed once stored, a rollback stores a new version with the
+// body of an older one.
+type Template struct {
+	Channel string `json:"channel"`
+	Name    string `json:"name"`
+
+	// Locale is the BCP 47 tag of the variant, empty for the one used when
+	// no variant matches the locale of the recipient. Every variant has
+	// its own versions.
+	Locale string `json:"locale,omitempty"`
+
+	// Version is 1 for the first one and increased by every create or
+	// rollback.
+	Version int32 `json:"version"`
+
+	// Engine is Text (default) or HTML.
+	Engine string `json:"engine"`
+
+	// Body is the source of the template, its variables are the ones sent
+	// with the messages.
+	Body string `json:"body"`
+
+	// ContentType is the type of the rendered content, see the content
+	// package. It can not be content.Protobuf.
+	ContentType string `json:"content_type,omitempty"`
+
+	// Client is the id of the client that stored the version, empty when
+	// the authentication is disabled.
+	Client string `json:"client,omitempty"`
+
+	// Time is when the version was stored.
+	Time time.Time `json:"time"`
+
+	// RollbackOf is the version restored by the rollback that stored this
+	// one, 0 otherwise.
+	RollbackOf int32 `json:"rollback_of,omitempty"`
+}
+
+// executor is the parsed template of either engine.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// parse parses the body with the engine of t. The missing variables fail
+// the rendering instead of printing "<no value>".
+func (t *Template) parse() (executor, error) {
+	switch t.Engine {
+	case "", Text:
+		return texttemplate.New(t.Name).Option("missingkey=error").Parse(t.Body)
+	case HTML:
+		return htmltemplate.New(t.Name).Option("missingkey=error").Parse(t.Body)
+	}
+
+	return nil, fmt.Errorf("unknown template engine %q, must be text or html", t.Engine)
+}
+
+// Validate returns an error if t can not be stored.
+func (t *Template) Validate() error {
+	if t.Channel == "" || t.Name == "" {
+		return errors.New("channel and name are required")
+	}
+	if l, err := ParseLocale(t.Locale); err != nil || l != t.Locale {
+		return fmt.Errorf("locale %q must be a canonical BCP 47 tag", t.Locale)
+	}
+	switch t.ContentType {
+	case "", content.Text, content.JSON:
+	default:
+		return fmt.Errorf("templates can not render %q content", t.ContentType)
+	}
+
+	_, err := t.parse()
+	return err
+}
+
+// Render renders t with the given variables, failing when one of the
+// variables used by t is missing or the result is not of its content
+// type.
+func (t *Template) Render(vars map[string]interface{}) (string, error) {
+	x, err := t.parse()
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := x.Execute(&b, vars); err != nil {
+		return "", err
+	}
+	if err := content.Check(t.ContentType, b.String()); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// ParseLocale returns the canonical form of the BCP 47 tag locale, so
+// "es_cl" and "es-CL" select the same variant. Empty and "und" are the
+// variant without locale.
+func ParseLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", err
+	}
+	if tag.IsRoot() {
+		return "", nil
+	}
+
+	return tag.String(), nil
+}
+
+// Fallbacks returns the locales of the variants tried for a recipient in
+// locale, from the most specific one: locale and its parents, then def and
+// its parents, and the variant without locale last. For es-CL and en it is
+// es-CL, es-419, es, en and "".
+func Fallbacks(locale, def string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+	for _, l := range []string{locale, def} {
+		if l == "" {
+			continue
+		}
+
+		tag, err := language.Parse(l)
+		if err != nil {
+			return nil, err
+		}
+		for ; !tag.IsRoot(); tag = tag.Parent() {
+			if s := tag.String(); !seen[s] {
+				seen[s] = true
+				chain = append(chain, s)
+			}
+		}
+	}
+
+	return append(chain, ""), nil
+}
+
+// ToProto ...
+func (t *Template) ToProto() *proto.Template {
+	return &proto.Template{
+		Channel:     t.Channel,
+		Name:        t.Name,
+		Locale:      t.Locale,
+		Version:     t.Version,
+		Engine:      t.Engine,
+		Body:        t.Body,
+		ContentType: t.ContentType,
+		Client:      t.Client,
+		Time:        t.Time.UnixNano() / int64(time.Millisecond),
+		RollbackOf:  t.RollbackOf,
+	}
+}
+
+// FromProto ...
+func (t *Template) FromProto(pt *proto.Template) *Template {
+	t.Channel = pt.Channel
+	t.Name = pt.Name
+	t.Locale = pt.Locale
+	t.Version = pt.Version
+	t.Engine = pt.Engine
+	t.Body = pt.Body
+	t.ContentType = pt.ContentType
+	t.Client = pt.Client
+	t.Time = time.Unix(0, pt.Time*int64(time.Millisecond))
+	t.RollbackOf = pt.RollbackOf
+
+	return t
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/microapis/messages-core/content"
+)
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		locale, def string
+		want        []string
+	}{
+		{"", "", []string{""}},
+		{"", "en", []string{"en", ""}},
+		{"es-CL", "en", []string{"es-CL", "es-419", "es", "en", ""}},
+		{"en-GB", "en", []string{"en-GB", "en-001", "en", ""}},
+		{"es", "es-CL", []string{"es", "es-CL", "es-419", ""}},
+	}
+
+	for _, tt := range tests {
+		got, err := Fallbacks(tt.locale, tt.def)
+		if err != nil {
+			t.Errorf("Fallbacks(%q, %q): %v", tt.locale, tt.def, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Fallbacks(%q, %q) = %q, want %q", tt.locale, tt.def, got, tt.want)
+		}
+	}
+
+	if _, err := Fallbacks("not a locale!", "en"); err == nil {
+		t.Error("Fallbacks of an invalid locale did not fail")
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		locale, want string
+		ok           bool
+	}{
+		{"", "", true},
+		{"und", "", true},
+		{"es_cl", "es-CL", true},
+		{"es-CL", "es-CL", true},
+		{"not a locale!", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLocale(tt.locale)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseLocale(%q) = %q, %v, want %q, ok %v", tt.locale, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		tpl  Template
+		vars map[string]interface{}
+		want string
+		ok   bool
+	}{
+		{"text", Template{Body: "Hi {{.name}}"}, map[string]interface{}{"name": "<b>"}, "Hi <b>", true},
+		{"html escapes", Template{Engine: HTML, Body: "Hi {{.name}}"}, map[string]interface{}{"name": "<b>"}, "Hi &lt;b&gt;", true},
+		{"missing variable", Template{Body: "Hi {{.name}}"}, map[string]interface{}{}, "", false},
+		{"unknown engine", Template{Engine: "md", Body: "Hi"}, nil, "", false},
+		{"json", Template{ContentType: content.JSON, Body: `{"n":{{.n}}}`}, map[string]interface{}{"n": 1}, `{"n":1}`, true},
+		{"invalid json", Template{ContentType: content.JSON, Body: `{"n":{{.n}}`}, map[string]interface{}{"n": 1}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.tpl.Render(tt.vars)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%s: Render() = %q, %v, want %q, ok %v", tt.name, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Default is the tenant of the requests without tenant id. Its data is
+// kept on the root bolt buckets and redis keys, as before the tenants.
+const Default = ""
+
+// Header is the metadata key of the tenant id.
+const Header = "x-tenant-id"
+
+// Config describes a tenant and its limits.
+type Config struct {
+	ID string `yaml:"id" toml:"id" json:"id"`
+
+	// MaxQueued is the maximum number of messages waiting to be delivered,
+	// 0 does not limit them.
+	MaxQueued int `yaml:"max_queued" toml:"max_queued" json:"max_queued"`
+
+	// Rate is the number of requests per second allowed to the tenant,
+	// with bursts of up to Burst requests. 0 does not limit them.
+	Rate  float64 `yaml:"rate" toml:"rate" json:"rate"`
+	Burst int     `yaml:"burst" toml:"burst" json:"burst"`
+}
+
+// FromContext returns the tenant id sent on the incoming metadata of ctx,
+// or Default when there is none.
+func FromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(Header); len(v) > 0 {
+		return v[0]
+	}
+
+	return Default
+}
+
+// Key returns key namespaced by tenant, the keys of the Default tenant
+// are not namespaced.
+func Key(tenant, key string) string {
+	if tenant == Default {
+		return key
+	}
+
+	return tenant + ":" + key
+}
+
+// Namespaced reports whether key belongs to a tenant other than Default.
+func Namespaced(key string) bool {
+	return strings.Contains(key, ":")
+}
@@ -0,0 +1,47 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		tenant, key, want string
+		namespaced        bool
+	}{
+		{Default, "pq:ids", "pq:ids", true},
+		{Default, "email", "email", false},
+		{"billing", "email", "billing:email", true},
+		{"billing", "pq:ids", "billing:pq:ids", true},
+	}
+
+	for _, tt := range tests {
+		got := Key(tt.tenant, tt.key)
+		if got != tt.want {
+			t.Errorf("Key(%q, %q) = %q, want %q", tt.tenant, tt.key, got, tt.want)
+		}
+		if Namespaced(got) != tt.namespaced {
+			t.Errorf("Namespaced(%q) = %v, want %v", got, !tt.namespaced, tt.namespaced)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	tests := []struct {
+		ctx  context.Context
+		want string
+	}{
+		{context.Background(), Default},
+		{metadata.NewIncomingContext(context.Background(), metadata.Pairs("other", "billing")), Default},
+		{metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "billing")), "billing"},
+	}
+
+	for _, tt := range tests {
+		if got := FromContext(tt.ctx); got != tt.want {
+			t.Errorf("FromContext() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// Config describes the TLS setup of a gRPC server and its clients.
+type Config struct {
+	// CertFile and KeyFile are the certificate presented to the peers,
+	// required by servers and by clients using mTLS.
+	CertFile string `yaml:"cert_file" toml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file" json:"key_file"`
+
+	// CAFile is the bundle used to verify the peer certificates, empty
+	// uses the system roots on clients.
+	CAFile string `yaml:"ca_file" toml:"ca_file" json:"ca_file"`
+
+	// ClientAuth makes servers require and verify the client certificates
+	// against CAFile.
+	ClientAuth bool `yaml:"client_auth" toml:"client_auth" json:"client_auth"`
+
+	// AllowedNames restricts the accepted peer certificates to the ones
+	// with one of these common names or DNS names, empty accepts every
+	// certificate signed by CAFile.
+	AllowedNames []string `yaml:"allowed_names" toml:"allowed_names" json:"allowed_names"`
+
+	// ReloadInterval is how often the files are checked for changes, 0
+	// checks them every minute.
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval" json:"reload_interval"`
+}
+
+// Enabled reports whether TLS is configured.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.CAFile != ""
+}
+
+// Validate ...
+func (c Config) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("cert_file and key_file must be set together")
+	}
+	if c.ClientAuth && c.CAFile == "" {
+		return errors.New("ca_file is required by client_auth")
+	}
+	if c.ReloadInterval < 0 {
+		return errors.New("reload_interval must not be negative")
+	}
+
+	return nil
+}
+
+// Credentials are the transport credentials of a Config, which watch its
+// files until they are closed.
+type Credentials struct {
+	credentials.TransportCredentials
+
+	r *reloader
+}
+
+// Close stops watching the files, the credentials keep working with the
+// last loaded ones.
+func (c *Credentials) Close() error {
+	c.r.stop()
+	return nil
+}
+
+// ServerCredentials returns the credentials of a gRPC server, reloading
+// the certificate and the CA when their files change.
+func (c Config) ServerCredentials() (*Credentials, error) {
+	if c.CertFile == "" {
+		return nil, errors.New("cert_file is required by servers")
+	}
+
+	r, err := newReloader(c)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.get()
+
+			cfg := &tls.Config{
+				MinVersion:            tls.VersionTLS12,
+				Certificates:          []tls.Certificate{*cert},
+				VerifyPeerCertificate: c.verifyNames,
+			}
+			if c.ClientAuth {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = pool
+			}
+			return cfg, nil
+		},
+	})
+
+	return &Credentials{creds, r}, nil
+}
+
+// ClientCredentials returns the credentials of a gRPC client, reloading
+// the client certificate and the CA when their files change.
+//
+// serverName is the name verified on the server certificate, empty uses
+// the host of the dialed address.
+func (c Config) ClientCredentials(serverName string) (*Credentials, error) {
+	r, err := newReloader(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// the server certificate is verified by VerifyConnection against
+		// the current CA pool, as the RootCAs cannot be reloaded.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, pool := r.get()
+			return c.verifyServer(cs, pool)
+		},
+	}
+	if c.CertFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.get()
+			return cert, nil
+		}
+	}
+
+	return &Credentials{credentials.NewTLS(cfg), r}, nil
+}
+
+// verifyServer verifies the certificate of the server of cs against pool,
+// or the system roots when nil, and AllowedNames.
+func (c Config) verifyServer(cs tls.ConnectionState, pool *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	chains, err := cs.PeerCertificates[0].Verify(opts)
+	if err != nil {
+		return err
+	}
+
+	return c.verifyNames(nil, chains)
+}
+
+// verifyNames checks the verified peer certificate against AllowedNames.
+func (c Config) verifyNames(_ [][]byte, chains [][]*x509.Certificate) error {
+	if len(c.AllowedNames) == 0 || len(chains) == 0 {
+		return nil
+	}
+
+	leaf := chains[0][0]
+	names := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+	for _, allowed := range c.AllowedNames {
+		for _, name := range names {
+			if name == allowed {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("peer certificate %q is not allowed", leaf.Subject.CommonName)
+}
+
+// reloader keeps the certificate and the CA pool of a Config, loading
+// them again when their files change.
+type reloader struct {
+	c Config
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newReloader(c Config) (*reloader, error) {
+	r := &reloader{c: c, done: make(chan struct{})}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	interval := c.ReloadInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	go r.watch(interval)
+
+	return r, nil
+}
+
+func (r *reloader) get() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, r.pool
+}
+
+// stop ends the watch of the files.
+func (r *reloader) stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+// load reads the files of the config.
+func (r *reloader) load() error {
+	var cert *tls.Certificate
+	if r.c.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.c.CertFile, r.c.KeyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.c.CAFile != "" {
+		b, err := os.ReadFile(r.c.CAFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return fmt.Errorf("%s: no certificates found", r.c.CAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = cert
+	r.pool = pool
+	r.modTime = r.lastModTime()
+	r.mu.Unlock()
+
+	return nil
+}
+
+// lastModTime returns the newest modification time of the files.
+func (r *reloader) lastModTime() time.Time {
+	var t time.Time
+	for _, name := range []string{r.c.CertFile, r.c.KeyFile, r.c.CAFile} {
+		if name == "" {
+			continue
+		}
+		if fi, err := os.Stat(name); err == nil && fi.ModTime().After(t) {
+			t = fi.ModTime()
+		}
+	}
+
+	return t
+}
+
+// watch reloads the files when they change until stop, it runs in its
+// goroutine.
+func (r *reloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+
+		r.mu.RLock()
+		changed := r.lastModTime().After(r.modTime)
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		// the old files are kept until the new ones can be loaded.
+		if err := r.load(); err != nil {
+			slog.Error("could not reload TLS files", "error", err)
+			continue
+		}
+		slog.Info("TLS files reloaded")
+	}
+}
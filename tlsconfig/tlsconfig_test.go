@@ -0,0 +1,145 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newCert returns a certificate for name signed by parent, or self signed
+// when parent is nil.
+func newCert(t *testing.T, name string, parent *tls.Certificate) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	signer, signerKey := tmpl, interface{}(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func writeCA(t *testing.T, path string, ca *tls.Certificate, modTime time.Time) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate[0]})
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake dials a TLS server presenting cert with creds.
+func handshake(creds *Credentials, cert *tls.Certificate) error {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		tls.Server(server, &tls.Config{Certificates: []tls.Certificate{*cert}}).Handshake()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, _, err := creds.ClientHandshake(ctx, "localhost", client)
+	return err
+}
+
+func TestClientCredentialsReloadCA(t *testing.T) {
+	oldCA := newCert(t, "old", nil)
+	newCA := newCert(t, "new", nil)
+	server := newCert(t, "localhost", newCA)
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	writeCA(t, caFile, oldCA, time.Now().Add(-time.Minute))
+
+	creds, err := Config{CAFile: caFile, ReloadInterval: 10 * time.Millisecond}.ClientCredentials("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer creds.Close()
+
+	if err := handshake(creds, server); err == nil {
+		t.Fatal("handshake verified against the old CA")
+	}
+
+	writeCA(t, caFile, newCA, time.Now())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := handshake(creds, server)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handshake with the reloaded CA: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestClientCredentialsAllowedNames(t *testing.T) {
+	ca := newCert(t, "ca", nil)
+	server := newCert(t, "localhost", ca)
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	writeCA(t, caFile, ca, time.Now())
+
+	tests := []struct {
+		allowed []string
+		ok      bool
+	}{
+		{nil, true},
+		{[]string{"localhost"}, true},
+		{[]string{"other"}, false},
+	}
+
+	for _, tt := range tests {
+		creds, err := Config{CAFile: caFile, AllowedNames: tt.allowed}.ClientCredentials("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handshake(creds, server); (err == nil) != tt.ok {
+			t.Errorf("handshake with allowed names %v = %v, want ok %v", tt.allowed, err, tt.ok)
+		}
+		creds.Close()
+	}
+}
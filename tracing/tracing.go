@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+const instrumentation = "github.com/microapis/messages-core"
+
+// Config ...
+type Config struct {
+	// Endpoint is the address of the OTLP gRPC collector, empty only
+	// propagates the trace context without exporting spans.
+	Endpoint string `yaml:"endpoint" toml:"endpoint" json:"endpoint"`
+
+	// Insecure disables TLS on the connection to the collector.
+	Insecure bool `yaml:"insecure" toml:"insecure" json:"insecure"`
+
+	// SampleRatio is the ratio of the traces sampled when they do not
+	// have a sampled parent, 0 samples them all.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" json:"sample_ratio"`
+}
+
+// Setup installs the W3C trace context propagator and, with an endpoint,
+// a tracer provider exporting the spans of service to it. The returned
+// func flushes the pending spans.
+func Setup(ctx context.Context, service string, c Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if c.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.Endpoint)}
+	if c.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if c.SampleRatio > 0 {
+		sampler = sdktrace.TraceIDRatioBased(c.SampleRatio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Start starts a span of the service.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, opts...)
+}
+
+// Record records err on span, if any.
+func Record(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	Record(span, err)
+	span.End()
+}
+
+// Inject returns the trace context of ctx, to be persisted with the
+// message. It is nil when ctx has no span.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	return carrier
+}
+
+// Extract returns ctx with the remote span of the persisted trace context
+// carrier.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// LinkTo links the span to the one of the persisted trace context, if
+// any. It is used by the spans that run on their own trace but are caused
+// by another one, as the delayed delivery of a message.
+func LinkTo(carrier map[string]string) trace.SpanStartOption {
+	link := trace.LinkFromContext(Extract(context.Background(), carrier))
+	if !link.SpanContext.IsValid() {
+		return trace.WithLinks()
+	}
+
+	return trace.WithLinks(link)
+}
+
+// Message returns the attributes of a message.
+func Message(id, channel, provider string) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("message.id", id),
+		attribute.String("message.channel", channel),
+		attribute.String("message.provider", provider),
+	)
+}
+
+// metadataCarrier adapts the gRPC metadata to the propagators.
+type metadataCarrier metadata.MD
+
+// Get ...
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// Set ...
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(strings.ToLower(key), value)
+}
+
+// Keys ...
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
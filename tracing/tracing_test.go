@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// record installs a tracer provider recording the ended spans.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	return sr
+}
+
+func TestInject(t *testing.T) {
+	record(t)
+
+	if c := Inject(context.Background()); c != nil {
+		t.Errorf("Inject() without span = %v, want nil", c)
+	}
+
+	ctx, span := Start(context.Background(), "put")
+	defer span.End()
+
+	carrier := Inject(ctx)
+	if carrier["traceparent"] == "" {
+		t.Fatalf("Inject() = %v, want traceparent", carrier)
+	}
+
+	sc := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	if !sc.IsRemote() || sc.TraceID() != span.SpanContext().TraceID() || sc.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Extract() = %v, want remote %v", sc, span.SpanContext())
+	}
+}
+
+func TestLinkTo(t *testing.T) {
+	sr := record(t)
+
+	ctx, put := Start(context.Background(), "put")
+	carrier := Inject(ctx)
+	put.End()
+
+	_, deliver := Start(context.Background(), "deliver", LinkTo(carrier))
+	deliver.End()
+	_, orphan := Start(context.Background(), "orphan", LinkTo(nil))
+	orphan.End()
+
+	spans := sr.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("ended %d spans, want 3", len(spans))
+	}
+
+	d := spans[1]
+	if d.Parent().IsValid() {
+		t.Errorf("deliver parent = %v, want its own trace", d.Parent())
+	}
+	if len(d.Links()) != 1 || d.Links()[0].SpanContext.SpanID() != put.SpanContext().SpanID() {
+		t.Errorf("deliver links = %v, want put", d.Links())
+	}
+	if links := spans[2].Links(); len(links) != 0 {
+		t.Errorf("orphan links = %v, want none", links)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	sr := record(t)
+
+	var server trace.SpanContext
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		server = trace.SpanContextFromContext(ctx)
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.SchedulerService/Get"}
+
+	// The client sends its trace context on the metadata, the server
+	// continues it from the incoming one.
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		_, err := UnaryServerInterceptor()(metadata.NewIncomingContext(context.Background(), md), req, info, handler)
+		return err
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	err := UnaryClientInterceptor()(ctx, info.FullMethod, nil, nil, nil, invoker)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("call = %v, want NotFound", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended %d spans, want 2", len(spans))
+	}
+
+	srv, cli := spans[0], spans[1]
+	if srv.SpanKind() != trace.SpanKindServer || cli.SpanKind() != trace.SpanKindClient {
+		t.Errorf("kinds = %v, %v, want server, client", srv.SpanKind(), cli.SpanKind())
+	}
+	if srv.Parent().SpanID() != cli.SpanContext().SpanID() || srv.SpanContext().TraceID() != cli.SpanContext().TraceID() {
+		t.Errorf("server parent = %v, want client %v", srv.Parent(), cli.SpanContext())
+	}
+	if server.SpanID() != srv.SpanContext().SpanID() {
+		t.Errorf("handler span = %v, want server %v", server, srv.SpanContext())
+	}
+
+	for _, s := range spans {
+		var code string
+		for _, a := range s.Attributes() {
+			if a.Key == "rpc.grpc.status_code" {
+				code = a.Value.AsString()
+			}
+		}
+		if code != codes.NotFound.String() {
+			t.Errorf("%s: status code = %q, want %q", s.Name(), code, codes.NotFound)
+		}
+		if s.Status().Description != "not found" {
+			t.Errorf("%s: status = %v, want error", s.Name(), s.Status())
+		}
+	}
+}